@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var specFile string //nolint:gochecknoglobals
+
+//nolint:gochecknoglobals
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "apply generates the resources described in a spec file",
+	Long: `apply generates the resources described in a YAML or JSON spec file. The
+whole file is validated before anything is generated, and resources are
+generated parents first, then in the order of the tables they reference. `,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+		if !skipGitCheck && !dryRun {
+			ensureCleanRepo()
+		}
+
+		gen := newGenerator()
+
+		if err := gen.CheckValidProject(cmd.Context(), workspaceFolder); err != nil {
+			panic(err)
+		}
+
+		spec, err := generator.LoadSpec(specFile)
+		if err != nil {
+			panic(err)
+		}
+
+		inputs, err := spec.Inputs(workspaceFolder)
+		if err != nil {
+			panic(err)
+		}
+
+		for _, input := range inputs {
+			log.Info().Str("service", input.Service.String()).Str("resource", input.Resource.String()).Msg("Generating resource")
+
+			generateResource(cmd.Context(), gen, input)
+		}
+
+		writeDryRunReport(gen)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	applyCmd.Flags().StringVarP(&specFile, "file", "f", "", "Spec file describing the resources")
+	applyCmd.Flags().BoolVar(&skipGitCheck, "skip-git", false, "Skip git check for uncommitted changes")
+	applyCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	applyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing them")
+	applyCmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "Skip resources that are already in the project")
+	applyCmd.Flags().BoolVar(&force, "force", false, "Replace resources that are already in the project, dropping their tables")
+	applyCmd.MarkFlagsMutuallyExclusive("skip-existing", "force")
+	applyCmd.MarkFlagRequired("file") //nolint:errcheck,gosec
+
+	rootCmd.AddCommand(applyCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals
+var destroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "destroy removes generated code from the project",
+	Long:  `destroy removes generated code from the project. `,
+}
+
+//nolint:gochecknoglobals
+var destroyResourceCmd = &cobra.Command{
+	Use:   "resource",
+	Short: "resource removes everything generated for a resource",
+	Long: `resource removes everything generated for a resource, and writes a
+migration that drops its table. Its many-to-many relations are removed with
+it. Resources nested under it, its children and resources referencing it
+have to be destroyed first.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+		log.Info().Msg("Destroying resource")
+
+		if !skipGitCheck && !dryRun {
+			ensureCleanRepo()
+		}
+
+		gen := newGenerator()
+
+		if err := gen.CheckValidProject(cmd.Context(), workspaceFolder); err != nil {
+			panic(err)
+		}
+
+		input := generator.Input{
+			WorkspaceFolder: workspaceFolder,
+			Service:         generator.TemplateName(service),
+			Resource:        generator.TemplateName(args[0]),
+		}
+
+		if err := gen.Destroy(cmd.Context(), input); err != nil {
+			logRollback(err)
+			panic(err)
+		}
+
+		writeDryRunReport(gen)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	destroyResourceCmd.Flags().BoolVar(&skipGitCheck, "skip-git", false, "Skip git check for uncommitted changes")
+	destroyResourceCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	destroyResourceCmd.Flags().StringVar(&service, "service", "", "Service that the resource belongs to (defaults to the one in the manifest)")
+	destroyResourceCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing them")
+
+	destroyCmd.AddCommand(destroyResourceCmd)
+	rootCmd.AddCommand(destroyCmd)
+}
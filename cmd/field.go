@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals
+var fieldCmd = &cobra.Command{
+	Use:   "field",
+	Short: "field changes the fields of an existing resource",
+	Long:  `field changes the fields of an existing resource. `,
+}
+
+//nolint:gochecknoglobals
+var fieldAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "add adds new fields to an existing resource",
+	Long: `add adds new fields to an existing resource, with an ALTER TABLE migration
+and update methods for fields marked as updateable.
+
+The table can already have rows, so not_null fields, and enum fields, which are
+always not null, need a default=... to fill the new column in for them. `,
+	Args: cobra.MinimumNArgs(2), //nolint:gomnd
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+		log.Info().Msg("Adding fields")
+
+		if !skipGitCheck && !dryRun {
+			ensureCleanRepo()
+		}
+
+		gen := newGenerator()
+
+		if err := gen.CheckValidProject(cmd.Context(), workspaceFolder); err != nil {
+			panic(err)
+		}
+
+		name := args[0]
+		fieldStrings := args[1:]
+
+		fields := []generator.InputField{}
+
+		for _, fieldString := range fieldStrings {
+			field, err := generator.ParseField(service, name, fieldString)
+			if err != nil {
+				panic(fmt.Errorf("failed parsing field %s: %w", fieldString, err))
+			}
+
+			fields = append(fields, field)
+		}
+
+		input := generator.Input{
+			WorkspaceFolder: workspaceFolder,
+			Service:         generator.TemplateName(service),
+			Resource:        generator.TemplateName(name),
+			Fields:          fields,
+		}
+
+		if err := gen.AddFields(cmd.Context(), input); err != nil {
+			logRollback(err)
+			panic(err)
+		}
+
+		writeDryRunReport(gen)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	fieldAddCmd.Flags().BoolVar(&skipGitCheck, "skip-git", false, "Skip git check for uncommitted changes")
+	fieldAddCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	fieldAddCmd.Flags().StringVar(&service, "service", "", "Service that the resource belongs to (defaults to the one in the manifest)")
+	fieldAddCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing them")
+
+	fieldCmd.AddCommand(fieldAddCmd)
+	rootCmd.AddCommand(fieldCmd)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/sparkymat/oxgen/internal/git"
+)
+
+var ErrUncommittedChanges = errors.New("uncommitted changes")
+
+func ensureCleanRepo() {
+	log.Info().Msg("Checking for uncommitted changes")
+
+	gitRepo, err := git.New()
+	if err != nil {
+		panic(err)
+	}
+
+	repoClean, err := gitRepo.StatusClean()
+	if err != nil {
+		panic(err)
+	}
+
+	if !repoClean {
+		panic(ErrUncommittedChanges)
+	}
+}
+
+func newGenerator() *generator.Service {
+	options := []generator.Option{
+		generator.WithTemplatesFolder(resolvedTemplatesFolder()),
+	}
+
+	if dryRun {
+		options = append(options, generator.WithDryRun())
+	}
+
+	if force {
+		options = append(options, generator.WithForce())
+	}
+
+	return generator.New(options...)
+}
+
+// resolvedTemplatesFolder is the --templates folder, or the project's
+// .oxgen/templates folder when the flag is not set.
+func resolvedTemplatesFolder() string {
+	if templatesFolder != "" {
+		return templatesFolder
+	}
+
+	return filepath.Join(workspaceFolder, ".oxgen", "templates")
+}
+
+func writeDryRunReport(gen *generator.Service) {
+	if !dryRun {
+		return
+	}
+
+	if err := gen.WriteDryRunReport(os.Stdout); err != nil {
+		panic(err)
+	}
+}
+
+// generateResource generates a resource, and with --skip-existing moves on
+// from resources that are already in the project instead of failing.
+func generateResource(ctx context.Context, gen *generator.Service, input generator.Input) {
+	err := gen.Generate(ctx, input)
+
+	var existingErr *generator.ExistingResourceError
+	if skipExisting && errors.As(err, &existingErr) {
+		log.Info().Str("resource", existingErr.Resource).Strs("found", existingErr.Artifacts).Msg("Skipping existing resource")
+
+		return
+	}
+
+	if err != nil {
+		logRollback(err)
+		panic(err)
+	}
+}
+
+func logRollback(err error) {
+	var rollbackErr *generator.RollbackError
+	if errors.As(err, &rollbackErr) {
+		log.Warn().Msg(rollbackErr.Summary())
+	}
+}
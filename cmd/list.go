@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list shows the resources and relations recorded in the manifest",
+	Long:  `list shows the resources and relations recorded in the project manifest (.oxgen/manifest.json). `,
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		manifest, err := generator.New().LoadManifest(workspaceFolder)
+		if err != nil {
+			panic(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+		fmt.Fprintln(w, "RESOURCE\tSERVICE\tPARENT\tFIELDS")
+
+		for _, entry := range manifest.Resources {
+			fieldNames := []string{}
+			for _, field := range entry.Fields {
+				fieldNames = append(fieldNames, field.Name)
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Resource, entry.Service, entry.Parent, strings.Join(fieldNames, ", "))
+		}
+
+		if len(manifest.Relations) > 0 {
+			fmt.Fprintln(w, "\nRELATION\tLEFT\tRIGHT")
+		}
+
+		for _, entry := range manifest.Relations {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Kind, entry.Left, entry.Right)
+		}
+
+		if err = w.Flush(); err != nil {
+			panic(err)
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	listCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+
+	rootCmd.AddCommand(listCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals
+var regenerateCmd = &cobra.Command{
+	Use:   "regenerate",
+	Short: "regenerate re-renders the files of a resource with the current templates",
+	Long: `regenerate re-renders the files a resource owns with the current templates,
+and merges the result with any hand edits made since they were generated.
+Where both changed the same lines, the file is left with conflict markers.
+The resource's queries are rendered afresh and sqlc is run again; hand edits
+to them are not kept.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+		log.Info().Msg("Regenerating resource")
+
+		if !skipGitCheck && !dryRun {
+			ensureCleanRepo()
+		}
+
+		gen := newGenerator()
+
+		if err := gen.CheckValidProject(cmd.Context(), workspaceFolder); err != nil {
+			panic(err)
+		}
+
+		conflicted, err := gen.Regenerate(cmd.Context(), generator.Input{
+			WorkspaceFolder: workspaceFolder,
+			Resource:        generator.TemplateName(args[0]),
+		})
+		if err != nil {
+			logRollback(err)
+			panic(err)
+		}
+
+		for _, path := range conflicted {
+			log.Warn().Str("file", path).Msg("Merge conflict")
+		}
+
+		writeDryRunReport(gen)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	regenerateCmd.Flags().BoolVar(&skipGitCheck, "skip-git", false, "Skip git check for uncommitted changes")
+	regenerateCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	regenerateCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing them")
+
+	rootCmd.AddCommand(regenerateCmd)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+//nolint:gochecknoglobals
+var relationCmd = &cobra.Command{
+	Use:   "relation",
+	Short: "relation relates existing resources to each other",
+	Long:  `relation relates existing resources to each other. `,
+}
+
+//nolint:gochecknoglobals
+var relationManyToManyCmd = &cobra.Command{
+	Use:   "many-to-many",
+	Short: "many-to-many relates two resources through a join table",
+	Long: `many-to-many relates two resources through a join table, and generates
+methods, routes and frontend endpoints to attach, detach and list records on
+both sides, e.g. POST /posts/:id/tags/:tag_id. `,
+	Args: cobra.ExactArgs(2), //nolint:gomnd
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+		log.Info().Msg("Generating relation")
+
+		if !skipGitCheck && !dryRun {
+			ensureCleanRepo()
+		}
+
+		gen := newGenerator()
+
+		if err := gen.CheckValidProject(cmd.Context(), workspaceFolder); err != nil {
+			panic(err)
+		}
+
+		input := generator.RelationInput{
+			WorkspaceFolder: workspaceFolder,
+			Left:            generator.TemplateName(args[0]),
+			Right:           generator.TemplateName(args[1]),
+		}
+
+		if err := gen.ManyToMany(cmd.Context(), input); err != nil {
+			logRollback(err)
+			panic(err)
+		}
+
+		writeDryRunReport(gen)
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	relationManyToManyCmd.Flags().BoolVar(&skipGitCheck, "skip-git", false, "Skip git check for uncommitted changes")
+	relationManyToManyCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	relationManyToManyCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing them")
+
+	relationCmd.AddCommand(relationManyToManyCmd)
+	rootCmd.AddCommand(relationCmd)
+}
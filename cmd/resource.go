@@ -1,19 +1,15 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"os"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/sparkymat/oxgen/internal/generator"
-	"github.com/sparkymat/oxgen/internal/git"
 	"github.com/spf13/cobra"
 )
 
-var ErrUncommittedChanges = errors.New("uncommitted changes")
-
 var skipGitCheck bool //nolint:gochecknoglobals
 
 var service string //nolint:gochecknoglobals
@@ -24,6 +20,26 @@ var searchField string //nolint:gochecknoglobals
 
 var parent string //nolint:gochecknoglobals
 
+var nested bool //nolint:gochecknoglobals
+
+var softDelete bool //nolint:gochecknoglobals
+
+var idStrategy string //nolint:gochecknoglobals
+
+var pagination string //nolint:gochecknoglobals
+
+var search string //nolint:gochecknoglobals
+
+var bulk bool //nolint:gochecknoglobals
+
+var upsertKey string //nolint:gochecknoglobals
+
+var dryRun bool //nolint:gochecknoglobals
+
+var skipExisting bool //nolint:gochecknoglobals
+
+var force bool //nolint:gochecknoglobals
+
 //nolint:gochecknoglobals
 var resourceCmd = &cobra.Command{
 	Use:   "resource",
@@ -35,24 +51,11 @@ var resourceCmd = &cobra.Command{
 
 		log.Info().Msg("Generating resource")
 
-		if !skipGitCheck {
-			log.Info().Msg("Checking for uncommitted changes")
-			gitRepo, err := git.New()
-			if err != nil {
-				panic(err)
-			}
-
-			repoClean, err := gitRepo.StatusClean()
-			if err != nil {
-				panic(err)
-			}
-
-			if !repoClean {
-				panic(ErrUncommittedChanges)
-			}
+		if !skipGitCheck && !dryRun {
+			ensureCleanRepo()
 		}
 
-		gen := generator.New()
+		gen := newGenerator()
 
 		if err := gen.CheckValidProject(cmd.Context(), workspaceFolder); err != nil {
 			panic(err)
@@ -61,6 +64,21 @@ var resourceCmd = &cobra.Command{
 		name := args[0]
 		fieldStrings := args[1:]
 
+		id, err := generator.ParseIDStrategy(idStrategy)
+		if err != nil {
+			panic(err)
+		}
+
+		listPagination, err := generator.ParsePagination(pagination)
+		if err != nil {
+			panic(err)
+		}
+
+		fullTextFields, err := generator.ParseSearch(search)
+		if err != nil {
+			panic(err)
+		}
+
 		fields := []generator.InputField{}
 
 		for _, fieldString := range fieldStrings {
@@ -73,25 +91,23 @@ var resourceCmd = &cobra.Command{
 		}
 
 		if parent != "" {
-			parentName := generator.TemplateName(parent)
-			fields = append(fields, generator.InputField{
-				Service:  generator.TemplateName(service),
-				Resource: generator.TemplateName(name),
-				Name:     generator.TemplateName(parentName.UnderscoreSingular() + "_id"),
-				Type:     generator.FieldTypeReferences,
-				Required: true,
-				Table:    parentName.UnderscorePlural(),
-				NotNull:  true,
-			})
+			fields = append(fields, generator.ParentField(service, name, parent))
 		}
 
 		input := generator.Input{
 			WorkspaceFolder: workspaceFolder,
-			HasSearch:       searchField != "",
+			HasSearch:       searchField != "" || len(fullTextFields) > 0,
 			Service:         generator.TemplateName(service),
 			Resource:        generator.TemplateName(name),
 			Fields:          fields,
 			SearchField:     searchField,
+			FullTextFields:  fullTextFields,
+			Nested:          nested,
+			SoftDelete:      softDelete,
+			ID:              id,
+			Pagination:      listPagination,
+			Bulk:            bulk,
+			UpsertKey:       upsertKey,
 		}
 
 		if parent != "" {
@@ -99,9 +115,9 @@ var resourceCmd = &cobra.Command{
 			input.Parent = &v
 		}
 
-		if err := gen.Generate(cmd.Context(), input); err != nil {
-			panic(err)
-		}
+		generateResource(cmd.Context(), gen, input)
+
+		writeDryRunReport(gen)
 	},
 }
 
@@ -110,8 +126,20 @@ func init() {
 	resourceCmd.Flags().BoolVar(&skipGitCheck, "skip-git", false, "Skip git check for uncommitted changes")
 	resourceCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
 	resourceCmd.Flags().StringVar(&searchField, "query-field", "", "Field to search by")
+	resourceCmd.Flags().StringVar(&search, "search", "", "Full-text search, e.g. fts:title,body for a ranked tsvector search of those fields. Needs offset pagination")
 	resourceCmd.Flags().StringVar(&service, "service", "", "Service that the resource belongs to")
 	resourceCmd.Flags().StringVar(&parent, "parent", "", "Parent resource")
+	resourceCmd.Flags().BoolVar(&nested, "nested", false, "Nest all routes under the parent, e.g. /posts/:parent_id/comments/:id")
+	resourceCmd.Flags().BoolVar(&softDelete, "soft-delete", false, "Mark records as deleted on destroy, with endpoints to list and restore them")
+	resourceCmd.Flags().StringVar(&idStrategy, "id", "uuid", "Primary key strategy: uuid, uuidv7, bigserial or ulid (needs github.com/oklog/ulid/v2)")
+	resourceCmd.Flags().StringVar(&pagination, "pagination", "offset", "Paging of the recent and search listings: offset, or cursor for keyset pages on (updated_at, id), or on (rank, id) for full-text search")
+	resourceCmd.Flags().BoolVar(&bulk, "bulk", false, "Add queries and a POST /<plural>/bulk endpoint creating and deleting many records at once")
+	resourceCmd.Flags().StringVar(&upsertKey, "upsert-key", "", "Unique, not null field that bulk upserts are keyed on (needs --bulk)")
+	resourceCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print a diff of the changes instead of writing them")
+	resourceCmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "Do nothing if the resource is already in the project")
+	resourceCmd.Flags().BoolVar(&force, "force", false, "Replace the resource if it is already in the project, dropping its table")
+	resourceCmd.MarkFlagsMutuallyExclusive("skip-existing", "force")
+	resourceCmd.MarkFlagsMutuallyExclusive("query-field", "search")
 	resourceCmd.MarkFlagRequired("service") //nolint:errcheck,gosec
 
 	rootCmd.AddCommand(resourceCmd)
@@ -5,13 +5,23 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var templatesFolder string //nolint:gochecknoglobals
+
 //nolint:gochecknoglobals
 var rootCmd = &cobra.Command{
 	Use:   "oxgen",
 	Short: "oxgen is a Go web-app project file generator",
 	Long: `oxgen generates files for adding new resources to a Go web-app project
 
-Note: oxgen assumes that the project follows certain conventions`,
+Note: oxgen assumes that the project follows certain conventions. Its
+Makefile needs the db-migrate, db-schema-dump and sqlc-gen targets, and a
+db-rollback target undoing the latest migration, which failed runs use to
+step the database back.`,
+}
+
+//nolint:gochecknoinits
+func init() {
+	rootCmd.PersistentFlags().StringVar(&templatesFolder, "templates", "", "Folder of templates that override the built-ins (defaults to .oxgen/templates)")
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var ErrResourceNotInManifest = errors.New("resource not found in manifest")
+
+//nolint:gochecknoglobals
+var showCmd = &cobra.Command{
+	Use:   "show",
+	Short: "show describes a resource recorded in the manifest",
+	Long: `show describes a resource recorded in the project manifest, with its fields
+and the files it generated. `,
+	Args: cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		manifest, err := generator.New().LoadManifest(workspaceFolder)
+		if err != nil {
+			panic(err)
+		}
+
+		entry, found := manifest.Find(args[0])
+		if !found {
+			panic(fmt.Errorf("%s: %w", args[0], ErrResourceNotInManifest))
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:gomnd
+
+		fmt.Fprintf(w, "Resource:\t%s\n", entry.Resource)
+		fmt.Fprintf(w, "Service:\t%s\n", entry.Service)
+
+		if entry.Parent != "" {
+			fmt.Fprintf(w, "Parent:\t%s\n", entry.Parent)
+		}
+
+		if entry.Nested {
+			fmt.Fprintln(w, "Nested:\tyes")
+		}
+
+		if entry.SoftDelete {
+			fmt.Fprintln(w, "Soft delete:\tyes")
+		}
+
+		if entry.ID != "" && entry.ID != generator.IDStrategyUUID {
+			fmt.Fprintf(w, "ID:\t%s\n", entry.ID)
+		}
+
+		if entry.Pagination == generator.PaginationCursor {
+			fmt.Fprintln(w, "Pagination:\tcursor")
+		}
+
+		if entry.SearchField != "" {
+			fmt.Fprintf(w, "Search field:\t%s\n", entry.SearchField)
+		}
+
+		if len(entry.FullTextFields) > 0 {
+			fmt.Fprintf(w, "Search:\tfts:%s\n", strings.Join(entry.FullTextFields, ","))
+		}
+
+		if entry.Bulk {
+			fmt.Fprintln(w, "Bulk:\tyes")
+		}
+
+		if entry.UpsertKey != "" {
+			fmt.Fprintf(w, "Upsert key:\t%s\n", entry.UpsertKey)
+		}
+
+		fmt.Fprintln(w, "\nFields:")
+
+		for _, field := range entry.Fields {
+			fieldType := string(field.Type)
+			if field.Array {
+				fieldType += "[]"
+			}
+
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", field.Name, fieldType, strings.Join(fieldFlags(field), " "))
+		}
+
+		fmt.Fprintln(w, "\nFiles:")
+
+		for _, path := range entry.Files {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+
+		fmt.Fprintln(w, "\nModified files:")
+
+		for _, path := range entry.Modified {
+			fmt.Fprintf(w, "  %s\n", path)
+		}
+
+		fmt.Fprintln(w, "\nAnchors:")
+
+		for _, anchor := range entry.Anchors {
+			fmt.Fprintf(w, "  %s\t%s\t(%s)\n", anchor.File, anchor.Anchor, anchor.Template)
+		}
+
+		if err = w.Flush(); err != nil {
+			panic(err)
+		}
+	},
+}
+
+func fieldFlags(field generator.ManifestField) []string {
+	flags := []string{}
+
+	if field.Table != "" {
+		flags = append(flags, "table="+field.Table)
+	}
+
+	if len(field.Values) > 0 {
+		flags = append(flags, fmt.Sprintf("values=%q", field.Values))
+	}
+
+	if field.GoType != "" {
+		flags = append(flags, "go_type="+field.GoType)
+	}
+
+	if field.Precision != 0 {
+		flags = append(flags, fmt.Sprintf("precision=%d", field.Precision))
+	}
+
+	if field.Scale != 0 {
+		flags = append(flags, fmt.Sprintf("scale=%d", field.Scale))
+	}
+
+	if field.Default != "" {
+		flags = append(flags, fmt.Sprintf("default=%q", field.Default))
+	}
+
+	if field.Unique {
+		flags = append(flags, "unique")
+	}
+
+	if len(field.UniqueTogether) > 0 {
+		flags = append(flags, "unique_together="+strings.Join(field.UniqueTogether, ","))
+	}
+
+	if field.Index {
+		flags = append(flags, "index")
+	}
+
+	if field.Check != "" {
+		flags = append(flags, fmt.Sprintf("check=%q", field.Check))
+	}
+
+	if field.NotNull {
+		flags = append(flags, "not_null")
+	}
+
+	if field.Updateable {
+		flags = append(flags, "updateable")
+	}
+
+	return flags
+}
+
+//nolint:gochecknoinits
+func init() {
+	showCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+
+	rootCmd.AddCommand(showCmd)
+}
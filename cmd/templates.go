@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/sparkymat/oxgen/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+var ErrTemplateExists = errors.New("template already ejected")
+
+var forceEject bool //nolint:gochecknoglobals
+
+//nolint:gochecknoglobals
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "templates manages the templates used for generation",
+	Long:  `templates manages the templates used for generation. `,
+}
+
+//nolint:gochecknoglobals
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list shows the names of the built-in templates",
+	Long:  `list shows the names of the built-in templates, and which of them are overridden. `,
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		for _, name := range generator.TemplateNames() {
+			fileName, _, err := generator.BuiltinTemplate(name)
+			if err != nil {
+				panic(err)
+			}
+
+			if _, err = os.Stat(filepath.Join(resolvedTemplatesFolder(), fileName)); err == nil {
+				fmt.Printf("%s (overridden)\n", name) //nolint:forbidigo
+
+				continue
+			}
+
+			fmt.Println(name) //nolint:forbidigo
+		}
+	},
+}
+
+//nolint:gochecknoglobals
+var templatesEjectCmd = &cobra.Command{
+	Use:   "eject [name]",
+	Short: "eject copies built-in templates out for customization",
+	Long: `eject copies a built-in template, or all of them when no name is given, into
+the templates folder, where edits to it override the built-in. `,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
+		names := generator.TemplateNames()
+		if len(args) == 1 {
+			names = args
+		}
+
+		folder := resolvedTemplatesFolder()
+
+		//nolint:gomnd
+		if err := os.MkdirAll(folder, 0o755); err != nil {
+			panic(err)
+		}
+
+		for _, name := range names {
+			fileName, content, err := generator.BuiltinTemplate(name)
+			if err != nil {
+				panic(err)
+			}
+
+			filePath := filepath.Join(folder, fileName)
+
+			if _, err = os.Stat(filePath); err == nil && !forceEject {
+				if len(args) == 1 {
+					panic(fmt.Errorf("%s: %w", filePath, ErrTemplateExists))
+				}
+
+				log.Info().Str("file", filePath).Msg("Skipping ejected template")
+
+				continue
+			}
+
+			//nolint:gomnd,gosec
+			if err = os.WriteFile(filePath, content, 0o644); err != nil {
+				panic(err)
+			}
+
+			log.Info().Str("file", filePath).Msg("Ejected template")
+		}
+	},
+}
+
+//nolint:gochecknoinits
+func init() {
+	templatesListCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	templatesEjectCmd.Flags().StringVar(&workspaceFolder, "path", ".", "Path to workspace")
+	templatesEjectCmd.Flags().BoolVar(&forceEject, "force", false, "Overwrite templates that were already ejected")
+
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesEjectCmd)
+	rootCmd.AddCommand(templatesCmd)
+}
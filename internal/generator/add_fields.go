@@ -0,0 +1,515 @@
+//nolint:lll,revive
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var (
+	ErrCreateQueryNotFound = errors.New("create query not found")
+	ErrFieldExists         = errors.New("field already exists")
+	ErrMergeConflicts      = errors.New("rendered files have conflicting edits")
+	ErrDefaultRequired     = errors.New("not null columns added to an existing table need a default")
+)
+
+type fileInjection struct {
+	filePath   string
+	anchorLine string
+	below      bool
+	name       string
+}
+
+// goInjection renders a template into a Go file, at a place found by parsing
+// the file, e.g. the end of a struct.
+type goInjection struct {
+	inject      func(filePath string, declaration string, templateName string, input any) error
+	filePath    string
+	declaration string
+	name        string
+}
+
+// AddFields adds new columns to an existing resource, and extends the code
+// generated for it to match.
+func (s *Service) AddFields(ctx context.Context, input Input) error {
+	if err := ensureValidResourceName(input.Resource.String()); err != nil {
+		return err
+	}
+
+	s.beginRun()
+
+	input, entry, err := s.resolveInput(input)
+	if err != nil {
+		return err
+	}
+
+	// the table can already have rows, which the new column must be filled in
+	// for
+	for _, field := range input.Fields {
+		if field.NotNull && field.Default == "" {
+			return fmt.Errorf("%s.%s: %w", input.Resource, field.Name, ErrDefaultRequired)
+		}
+	}
+
+	if entry != nil {
+		for _, field := range input.Fields {
+			for _, existing := range entry.Fields {
+				if existing.Name == field.Name.String() {
+					return fmt.Errorf("%s.%s: %w", input.Resource, field.Name, ErrFieldExists)
+				}
+			}
+		}
+
+		// new constraints can span the resource's existing columns too
+		columns := append(input.columnNames(), lo.Map(entry.Fields, func(f ManifestField, _ int) string { return f.Name })...)
+
+		if err = input.ensureConstraintFields(columns); err != nil {
+			return err
+		}
+
+		// bulk writes take the new columns too
+		resource, err := s.resourceWithFields(input)
+		if err != nil {
+			return err
+		}
+
+		if err = resource.ensureBulkFields(); err != nil {
+			return err
+		}
+	}
+
+	if err = s.addFields(ctx, input); err != nil {
+		if s.dryRun {
+			return err
+		}
+
+		return s.rollback(input, err)
+	}
+
+	return nil
+}
+
+//nolint:funlen,cyclop
+func (s *Service) addFields(ctx context.Context, input Input) error {
+	presenterPath := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api", "presenter", input.Resource.UnderscoreSingular()+".go")
+	if !s.fileExists(presenterPath) {
+		return fmt.Errorf("%s: %w", input.Resource.String(), ErrResourceNotFound)
+	}
+
+	input, err := s.resolveJSONTypes(input)
+	if err != nil {
+		return err
+	}
+
+	if input, err = s.resolveKeys(input); err != nil {
+		return err
+	}
+
+	// migration
+	if err := s.generateAddColumnsMigration(ctx, input); err != nil {
+		return fmt.Errorf("failed generating add columns migration: %w", err)
+	}
+
+	// run migration, dump schema and generate models
+	if err := s.runCommand(input.WorkspaceFolder, "make", "db-migrate"); err != nil {
+		return fmt.Errorf("failed running make db-migrate: %w", err)
+	}
+
+	s.workspace.migrations++
+
+	if err := s.trackFile(filepath.Join(input.WorkspaceFolder, "internal", "database", "schema.sql")); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "db-schema-dump"); err != nil {
+		return fmt.Errorf("failed running make db-schema-dump: %w", err)
+	}
+
+	// the bulk queries and methods are rendered again from all the fields
+	resource, err := s.resourceWithFields(input)
+	if err != nil {
+		return err
+	}
+
+	// extend create and add update sql methods
+	if err := s.extendCreateSQLMethod(input); err != nil {
+		return fmt.Errorf("failed extending create sql method: %w", err)
+	}
+
+	if resource.Bulk {
+		if err := s.extendBulkSQLMethods(ctx, resource); err != nil {
+			return fmt.Errorf("failed extending bulk sql methods: %w", err)
+		}
+	}
+
+	// the listings whitelist the fields they filter and sort by
+	extendsListings := resource.Resource != "" && input.extendsListings()
+
+	if extendsListings {
+		if err := s.extendListingSQLMethods(ctx, resource); err != nil {
+			return fmt.Errorf("failed extending listing sql methods: %w", err)
+		}
+	}
+
+	if err := s.generateFieldSQLMethods(ctx, input); err != nil {
+		return fmt.Errorf("failed generating sql methods: %w", err)
+	}
+
+	// point sqlc at the types of json fields
+	if err := s.generateJSONTypes(ctx, input); err != nil {
+		return fmt.Errorf("failed generating json types: %w", err)
+	}
+
+	// run sqlc gen
+	if err := s.trackFolder(filepath.Join(input.WorkspaceFolder, "internal", "dbx")); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "sqlc-gen"); err != nil {
+		return fmt.Errorf("failed running make sqlc-gen: %w", err)
+	}
+
+	if err := s.appendFieldDBMethodsToIface(ctx, input); err != nil {
+		return fmt.Errorf("failed appending new methods to database_iface.go: %w", err)
+	}
+
+	if resource.Bulk {
+		if err := s.extendBulkDBMethods(resource); err != nil {
+			return fmt.Errorf("failed extending bulk methods in database_iface.go: %w", err)
+		}
+	}
+
+	// extend the generated create, presenter and frontend code
+	if err := s.injectFieldTemplates(input, extendsListings); err != nil {
+		return err
+	}
+
+	if err := s.mergeFieldFiles(ctx, resource, extendsListings); err != nil {
+		return err
+	}
+
+	// add update methods for the new updateable fields
+	if err := s.writeServiceMethodFiles(
+		ctx,
+		filepath.Join(input.WorkspaceFolder, "internal", "service", input.Service.String()),
+		fieldServiceMethodFiles(input),
+	); err != nil {
+		return fmt.Errorf("failed adding service methods: %w", err)
+	}
+
+	if err := s.addFieldServiceMethodsToIface(ctx, input); err != nil {
+		return fmt.Errorf("failed adding service methods to interface: %w", err)
+	}
+
+	if err := s.writeHandlerMethodFiles(
+		ctx,
+		filepath.Join(input.WorkspaceFolder, "internal", "handler", "api"),
+		fieldHandlerMethodFiles(input),
+	); err != nil {
+		return fmt.Errorf("failed adding handler methods: %w", err)
+	}
+
+	if err := s.appendFieldRoutes(ctx, input); err != nil {
+		return fmt.Errorf("failed appending routes: %w", err)
+	}
+
+	if err := s.recordFields(ctx, input); err != nil {
+		return fmt.Errorf("failed updating manifest: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) generateAddColumnsMigration(_ context.Context, input Input) error {
+	if err := s.ensureFolderExists(filepath.Join(input.WorkspaceFolder, "migrations")); err != nil {
+		return err
+	}
+
+	timestamp, err := s.nextMigrationVersion(filepath.Join(input.WorkspaceFolder, "migrations"))
+	if err != nil {
+		return err
+	}
+	fieldNames := lo.Map(input.Fields, func(f InputField, _ int) string { return f.Name.UnderscoreSingular() })
+	migrationName := fmt.Sprintf("%s_add_%s_to_%s", timestamp, strings.Join(fieldNames, "_"), input.Resource.UnderscorePlural())
+
+	upSQL, err := s.renderTemplate("add_columns_up", input)
+	if err != nil {
+		return fmt.Errorf("failed to render up template: %w", err)
+	}
+
+	if err = s.writeFile(filepath.Join(input.WorkspaceFolder, "migrations", migrationName+".up.sql"), upSQL); err != nil {
+		return fmt.Errorf("failed to create up file: %w", err)
+	}
+
+	downSQL, err := s.renderTemplate("add_columns_down", input)
+	if err != nil {
+		return fmt.Errorf("failed to render down template: %w", err)
+	}
+
+	if err = s.writeFile(filepath.Join(input.WorkspaceFolder, "migrations", migrationName+".down.sql"), downSQL); err != nil {
+		return fmt.Errorf("failed to create down file: %w", err)
+	}
+
+	return nil
+}
+
+// extendCreateSQLMethod adds the new columns to the column and value lists
+// of the resource's Create query.
+func (s *Service) extendCreateSQLMethod(input Input) error {
+	queriesFilePath := filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql")
+
+	lines, err := s.readLines(queriesFilePath)
+	if err != nil {
+		return err
+	}
+
+	queryName := "Create" + input.Resource.CamelcaseSingular()
+	columns := lo.Map(input.Fields, func(f InputField, _ int) string { return f.Name.String() })
+	values := lo.Map(input.Fields, func(f InputField, _ int) string { return "@" + f.Name.String() + "::" + f.SQLType() })
+
+	inQuery := false
+	listsExtended := 0
+
+	for i, line := range lines {
+		if name, found := sqlQueryName(line); found {
+			inQuery = name == queryName
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if !inQuery || !strings.HasPrefix(trimmed, "(") || !strings.HasSuffix(trimmed, ")") {
+			continue
+		}
+
+		additions := columns
+		if listsExtended > 0 {
+			additions = values
+		}
+
+		existing := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+		if existing != "" {
+			additions = append([]string{existing}, additions...)
+		}
+
+		lines[i] = "(" + strings.Join(additions, ", ") + ")"
+
+		listsExtended++
+		//nolint:gomnd
+		if listsExtended == 2 {
+			return s.writeLinesToFile(lines, queriesFilePath)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", queryName, ErrCreateQueryNotFound)
+}
+
+// resourceWithFields is the resource as the manifest records it, with the new
+// fields added. Resources missing from the manifest are returned empty.
+func (s *Service) resourceWithFields(input Input) (Input, error) {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return Input{}, err
+	}
+
+	entry, found := manifest.Find(input.Resource.String())
+	if !found {
+		return Input{}, nil
+	}
+
+	entry.Fields = append(entry.Fields, manifestFields(input.Fields)...)
+
+	return entry.Input(input.WorkspaceFolder), nil
+}
+
+// extendBulkSQLMethods renders the resource's bulk queries again, since they
+// list every column they write.
+func (s *Service) extendBulkSQLMethods(ctx context.Context, input Input) error {
+	queriesFilePath := filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql")
+
+	if err := s.removeSQLQueries(queriesFilePath, bulkDBMethodNames(input)); err != nil {
+		return err
+	}
+
+	return s.appendTemplateToFile(ctx, queriesFilePath, "bulk_sql_methods", input)
+}
+
+// extendBulkDBMethods declares the bulk queries again, as sqlc gives them a
+// params struct once they write more than one column.
+func (s *Service) extendBulkDBMethods(input Input) error {
+	folderPath := filepath.Join(input.WorkspaceFolder, "internal", "service")
+	ifaceFilePath := filepath.Join(folderPath, "database_iface.go")
+
+	if err := s.removeMethodsFromFile(ifaceFilePath, bulkDBMethodNames(input)); err != nil {
+		return err
+	}
+
+	if err := s.injectIntoInterface(ifaceFilePath, "DatabaseProvider", "bulk_db_methods_iface", input); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(folderPath, "goimports", "-w", "database_iface.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
+
+// extendListingSQLMethods renders the resource's listing queries again, since
+// they list every column they filter and sort by.
+func (s *Service) extendListingSQLMethods(ctx context.Context, input Input) error {
+	queriesFilePath := filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql")
+
+	if err := s.removeSQLQueries(queriesFilePath, listingDBMethodNames(input)); err != nil {
+		return err
+	}
+
+	return s.generateListingSQLMethods(ctx, input)
+}
+
+// mergeFieldFiles renders the resource's files that list all of its fields
+// again, and merges them with any hand edits: the bulk service methods and
+// handler, and the listings when they whitelist the new fields.
+func (s *Service) mergeFieldFiles(ctx context.Context, input Input, extendsListings bool) error {
+	relativePaths := []string{}
+
+	if input.Bulk {
+		relativePaths = append(relativePaths,
+			"internal/service/"+input.Service.String()+"/bulk_"+input.Resource.UnderscorePlural()+".go",
+			"internal/handler/api/"+input.Resource.UnderscorePlural()+"_bulk.go",
+		)
+	}
+
+	if extendsListings {
+		relativePaths = append(relativePaths,
+			"internal/service/"+input.Service.String()+"/fetch_recent_"+input.Resource.UnderscorePlural()+".go",
+			"internal/service/"+input.Service.String()+"/search_"+input.Resource.UnderscorePlural()+".go",
+			"internal/handler/api/"+input.Resource.UnderscorePlural()+"_fetch_recent.go",
+			"internal/handler/api/"+input.Resource.UnderscorePlural()+"_search.go",
+		)
+	}
+
+	if len(relativePaths) == 0 {
+		return nil
+	}
+
+	rendered, err := s.renderResourceFiles(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	conflicted := []string{}
+
+	for _, relativePath := range relativePaths {
+		content, found := rendered[relativePath]
+		if !found {
+			continue
+		}
+
+		conflicts, err := s.mergeRenderedFile(input.WorkspaceFolder, relativePath, content)
+		if err != nil {
+			return err
+		}
+
+		if conflicts {
+			conflicted = append(conflicted, relativePath)
+		}
+	}
+
+	if len(conflicted) > 0 {
+		return fmt.Errorf("%s: %w", strings.Join(conflicted, ", "), ErrMergeConflicts)
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func (s *Service) injectFieldTemplates(input Input, extendsListings bool) error {
+	serviceFolder := filepath.Join(input.WorkspaceFolder, "internal", "service", input.Service.String())
+	handlerFolder := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api")
+	frontendFolder := filepath.Join(input.WorkspaceFolder, "frontend", "src")
+
+	createServicePath := filepath.Join(serviceFolder, "create_"+input.Resource.UnderscoreSingular()+".go")
+	createHandlerPath := filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_create.go")
+	presenterPath := filepath.Join(handlerFolder, "presenter", input.Resource.UnderscoreSingular()+".go")
+	modelPath := filepath.Join(frontendFolder, "models", input.Resource.CamelcaseSingular()+".ts")
+	slicePath := filepath.Join(frontendFolder, "slices", input.Resource.CamelcaseSingular()+".ts")
+
+	// the Go files are patched by declaration, so that they survive gofmt and
+	// hand edits
+	goInjections := []goInjection{
+		{s.injectIntoStruct, createServicePath, "Create" + input.Resource.CamelcaseSingular() + "Params", "add_create_params"},
+		{s.injectIntoLiteral, createServicePath, "dbx.Create" + input.Resource.CamelcaseSingular() + "Params", "add_create_assign_params"},
+		{s.injectIntoStruct, createHandlerPath, input.Resource.CamelcasePlural() + "CreateRequest", "add_create_request"},
+		{s.injectBelowLiteral, createHandlerPath, input.Service.String() + ".Create" + input.Resource.CamelcaseSingular() + "Params", "add_create_handler_assign_params"},
+		{s.injectIntoStruct, presenterPath, input.Resource.CamelcaseSingular(), "add_presenter_fields"},
+		{s.injectAboveReturn, presenterPath, input.Resource.CamelcaseSingular() + "FromModel", "add_presenter_assignments"},
+	}
+
+	for _, injection := range goInjections {
+		rendered, err := s.renderTemplate(injection.name, input)
+		if err != nil {
+			return err
+		}
+
+		// nothing to add, e.g. no initial fields
+		if strings.TrimSpace(string(rendered)) == "" {
+			continue
+		}
+
+		if err := injection.inject(injection.filePath, injection.declaration, injection.name, input); err != nil {
+			return fmt.Errorf("failed to extend %s: %w", injection.filePath, err)
+		}
+	}
+
+	injections := []fileInjection{
+		{modelPath, "export class " + input.Resource.CamelcaseSingular() + " {", false, "add_frontend_model_enum_types"},
+		{modelPath, "public updatedAt: dayjs.Dayjs;", true, "add_frontend_model_declarations"},
+		{modelPath, "this.updatedAt = dayjs.utc(json.updatedAt);", true, "add_frontend_model_assignments"},
+		{slicePath, "export interface CreateRequest {", true, "add_frontend_create_request"},
+		{slicePath, "export const api = createApi({", false, "add_frontend_update_requests"},
+		{slicePath, "endpoints: builder => ({", true, "add_frontend_update_endpoints"},
+		{slicePath, "useDestroyMutation", false, "add_frontend_update_hooks"},
+	}
+
+	if extendsListings {
+		injections = append(injections, fileInjection{slicePath, "export interface Filters {", true, "add_frontend_filter_declarations"})
+
+		if !input.CursorPagination() {
+			injections = append(injections, fileInjection{slicePath, "export type SortKey =", true, "add_frontend_sort_keys"})
+		}
+	}
+
+	for _, injection := range injections {
+		rendered, err := s.renderTemplate(injection.name, input)
+		if err != nil {
+			return err
+		}
+
+		// nothing to add, e.g. no updateable fields
+		if strings.TrimSpace(string(rendered)) == "" {
+			continue
+		}
+
+		if injection.below {
+			err = s.injectTemplateBelowLine(injection.filePath, injection.anchorLine, injection.name, input)
+		} else {
+			err = s.injectTemplateAboveLine(injection.filePath, injection.anchorLine, injection.name, input)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to extend %s: %w", injection.filePath, err)
+		}
+	}
+
+	for _, goFile := range []string{createServicePath, createHandlerPath, presenterPath} {
+		if err := s.runCommand(filepath.Dir(goFile), "goimports", "-w", filepath.Base(goFile)); err != nil {
+			return fmt.Errorf("failed running goimports: %w", err)
+		}
+	}
+
+	return nil
+}
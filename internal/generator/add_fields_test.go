@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddFieldsExtendsBulkQueries(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	tag := testInput(t, workspaceFolder, "Tag", "name:string:unique:not_null")
+	tag.Bulk = true
+	tag.UpsertKey = "name"
+
+	if err := New().Generate(context.Background(), tag); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	if err := New().AddFields(context.Background(), testInput(t, workspaceFolder, "Tag", "color:string")); err != nil {
+		t.Fatalf("failed to add fields: %v", err)
+	}
+
+	queries := readTestFile(t, filepath.Join(workspaceFolder, "internal", "database", "queries.sql"))
+
+	for _, query := range []string{"CreateManyTags", "UpsertTags"} {
+		if count := strings.Count(queries, "-- name: "+query+" "); count != 1 {
+			t.Errorf("expected %s once, got %d", query, count)
+		}
+	}
+
+	if !strings.Contains(queries, "(id, name, color)\nSELECT unnest(@id::uuid[]), unnest(@name::text[]), unnest(@color::text[])") {
+		t.Errorf("expected CreateManyTags to write color, got:\n%s", queries)
+	}
+
+	if !strings.Contains(queries, "SET color = EXCLUDED.color") {
+		t.Errorf("expected UpsertTags to overwrite color, got:\n%s", queries)
+	}
+
+	bulkService := readTestFile(t, filepath.Join(workspaceFolder, "internal", "service", "blog", "bulk_tags.go"))
+	if !strings.Contains(bulkService, "return item.Color") {
+		t.Errorf("expected the bulk service methods to pass color, got:\n%s", bulkService)
+	}
+}
+
+func TestAddFieldsAfterHandEdits(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Post", "title:string")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	edits := map[string][][2]string{
+		filepath.Join("internal", "service", "blog", "create_post.go"): {
+			{"type CreatePostParams struct {", "type CreatePostParams struct { // hand edited"},
+			{"input := dbx.CreatePostParams{", "input := dbx.CreatePostParams{ // hand edited"},
+		},
+		filepath.Join("internal", "handler", "api", "posts_create.go"): {
+			{"input := blog.CreatePostParams{}", "input := blog.CreatePostParams{} // hand edited"},
+		},
+		filepath.Join("internal", "handler", "api", "presenter", "post.go"): {
+			{"return item", "return item // hand edited"},
+		},
+	}
+
+	for relativePath, replacements := range edits {
+		filePath := filepath.Join(workspaceFolder, relativePath)
+		content := readTestFile(t, filePath)
+
+		for _, replacement := range replacements {
+			if !strings.Contains(content, replacement[0]) {
+				t.Fatalf("expected %s to contain %q", relativePath, replacement[0])
+			}
+
+			content = strings.Replace(content, replacement[0], replacement[1], 1)
+		}
+
+		if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to edit %s: %v", relativePath, err)
+		}
+	}
+
+	if err := New().AddFields(context.Background(), testInput(t, workspaceFolder, "Post", "rank:int:default=0:not_null")); err != nil {
+		t.Fatalf("failed to add fields: %v", err)
+	}
+
+	expected := map[string][]string{
+		filepath.Join("internal", "service", "blog", "create_post.go"):      {"Rank int32", "Rank: params.Rank,"},
+		filepath.Join("internal", "handler", "api", "posts_create.go"):      {"`json:\"rank\"`", "input.Rank = request.Rank"},
+		filepath.Join("internal", "handler", "api", "presenter", "post.go"): {"`json:\"rank\"`", "item.Rank = m.Rank"},
+		filepath.Join("internal", "handler", "api", "posts_fetch_recent.go"): {
+			`"rank", "rank[in]", "rank[gte]", "rank[lte]"`,
+			`postSortKeys   = []string{"title", "rank", "created_at", "updated_at"}`,
+		},
+		filepath.Join("internal", "service", "blog", "fetch_recent_posts.go"): {`filters.Value("rank[gte]")`},
+		filepath.Join("internal", "database", "queries.sql"):                  {"t.rank >= sqlc.narg('filter_rank_gte')", "THEN t.rank END DESC"},
+		filepath.Join("frontend", "src", "slices", "Post.ts"):                 {"rank?: { eq?: number;", "| '-rank'"},
+	}
+
+	for relativePath, snippets := range expected {
+		content := readTestFile(t, filepath.Join(workspaceFolder, relativePath))
+
+		for _, snippet := range snippets {
+			if !strings.Contains(strings.Join(strings.Fields(content), " "), strings.Join(strings.Fields(snippet), " ")) {
+				t.Errorf("expected %s to contain %q, got:\n%s", relativePath, snippet, content)
+			}
+		}
+	}
+}
+
+func TestAddFieldsRequiresDefaultForNotNull(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Post", "title:string")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	for _, field := range []string{"rank:int:not_null", "status:enum:values=draft,published"} {
+		err := New().AddFields(context.Background(), testInput(t, workspaceFolder, "Post", field))
+		if !errors.Is(err, ErrDefaultRequired) {
+			t.Errorf("expected %v adding %s, got %v", ErrDefaultRequired, field, err)
+		}
+	}
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Author", "name:string")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	if err := New().AddFields(context.Background(), testInput(t, workspaceFolder, "Post", "author_id:references:table=authors")); err != nil {
+		t.Fatalf("failed to add fields: %v", err)
+	}
+
+	presenter := readTestFile(t, filepath.Join(workspaceFolder, "internal", "handler", "api", "presenter", "post.go"))
+	if !strings.Contains(presenter, "if m.AuthorID.Valid {") {
+		t.Errorf("expected the nullable reference to be checked, got:\n%s", presenter)
+	}
+}
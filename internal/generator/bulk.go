@@ -0,0 +1,241 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/samber/lo"
+)
+
+var ErrInvalidBulk = errors.New("invalid bulk operations")
+
+// BulkFields are the columns bulk creates and upserts write: those of the
+// create params. Each is sent as an array and unnested into rows.
+func (i Input) BulkFields() []InputField {
+	return lo.Filter(i.Fields, func(f InputField, _ int) bool { return f.Initial() })
+}
+
+// ensureBulkFields checks that the resource's columns can be unnested from
+// arrays, which arrays and json can't, and that its upsert key is a unique
+// column that every item has a value for.
+func (i Input) ensureBulkFields() error {
+	if !i.Bulk {
+		if i.UpsertKey != "" {
+			return fmt.Errorf("%s: an upsert key needs bulk operations: %w", i.Resource, ErrInvalidBulk)
+		}
+
+		return nil
+	}
+
+	if len(i.BulkFields()) == 0 && !i.ID.AppGenerated() {
+		return fmt.Errorf("%s: has no fields to create records with: %w", i.Resource, ErrInvalidBulk)
+	}
+
+	for _, field := range i.BulkFields() {
+		if field.Array || field.Type == FieldTypeJSON {
+			return fmt.Errorf("%s: %s can't be written in bulk: %w", i.Resource, field.Name, ErrInvalidBulk)
+		}
+	}
+
+	if i.UpsertKey == "" {
+		return nil
+	}
+
+	key, found := i.upsertKeyField()
+	if !found || !key.Unique || !key.NotNull || !lo.Contains(upsertKeyTypes, key.Type) {
+		return fmt.Errorf("%s: %s is not a unique, not null field: %w", i.Resource, i.UpsertKey, ErrInvalidBulk)
+	}
+
+	return nil
+}
+
+// upsertKeyTypes are the types of the fields items can be told apart by.
+//
+//nolint:gochecknoglobals
+var upsertKeyTypes = []FieldType{
+	FieldTypeString,
+	FieldTypeEnum,
+	FieldTypeInt,
+	FieldTypeBigint,
+	FieldTypeUUID,
+	FieldTypeReferences,
+}
+
+func (i Input) upsertKeyField() (InputField, bool) {
+	return lo.Find(i.Fields, func(f InputField) bool { return f.Name.String() == i.UpsertKey })
+}
+
+// UpsertKeyGoType is the Go type of the upsert key, which the bulk handler
+// matches the upserted records with the items by.
+func (i Input) UpsertKeyGoType() string {
+	key, _ := i.upsertKeyField()
+
+	return key.GoType()
+}
+
+// UpsertKeyItemGo and UpsertKeyRecordGo are the names of the upsert key in
+// the create params and in the dbx model.
+func (i Input) UpsertKeyItemGo() string {
+	return TemplateName(i.UpsertKey).CamelcaseSingular()
+}
+
+func (i Input) UpsertKeyRecordGo() string {
+	return sqlcFieldName(i.UpsertKey)
+}
+
+// ParentRecordGo is the name of the parent field in the dbx model, which
+// nested bulk deletes tell the items under the parent by.
+func (i Input) ParentRecordGo() string {
+	return sqlcFieldName(i.Parent.UnderscoreSingular() + "_id")
+}
+
+// bulkWritesID is true if a bulk query is given the keys of the rows it
+// inserts. Bulk creates always are, since Postgres returns inserted rows in
+// no particular order, and the keys are what puts them back in the order of
+// the items.
+func (i Input) bulkWritesID(query string) bool {
+	return query == "CreateMany" || i.ID.AppGenerated()
+}
+
+// ReservesBulkIDs is true if the keys of bulk created rows are taken from the
+// table's sequence before they are inserted, rather than made by the service.
+func (i Input) ReservesBulkIDs() bool {
+	return i.ID.orDefault() == IDStrategyBigserial
+}
+
+// BulkColumnsSQL are the columns a bulk create or upsert inserts.
+func (i Input) BulkColumnsSQL(query string) string {
+	columns := lo.Map(i.BulkFields(), func(f InputField, _ int) string { return f.Name.String() })
+
+	if i.bulkWritesID(query) {
+		columns = append([]string{"id"}, columns...)
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// BulkValuesSQL unnest the array params of a bulk create or upsert into rows,
+// one for each item.
+func (i Input) BulkValuesSQL(query string) string {
+	values := lo.Map(i.BulkFields(), func(f InputField, _ int) string {
+		return "unnest(@" + f.Name.String() + "::" + f.SQLType() + "[])"
+	})
+
+	if i.bulkWritesID(query) {
+		values = append([]string{"unnest(@id::" + i.ID.SQLType() + "[])"}, values...)
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// UpsertSetSQL overwrites the columns of the rows an upsert conflicts with.
+// The key and parent stay as they are, and upserting a deleted row restores
+// it.
+func (i Input) UpsertSetSQL() string {
+	assignments := []string{}
+
+	for _, field := range i.BulkFields() {
+		if field.Name.String() == i.UpsertKey || i.IsParentField(field) {
+			continue
+		}
+
+		assignments = append(assignments, field.Name.String()+" = EXCLUDED."+field.Name.String())
+	}
+
+	if i.SoftDelete {
+		assignments = append(assignments, "deleted_at = NULL")
+	}
+
+	// the row is still returned, and its updated_at touched, when there is
+	// nothing else to set
+	if len(assignments) == 0 {
+		assignments = append(assignments, i.UpsertKey+" = EXCLUDED."+i.UpsertKey)
+	}
+
+	return strings.Join(assignments, ",\n      ")
+}
+
+// bulkArgs are the array params of a bulk create or upsert query, with the Go
+// expressions building them from the items of the service methods. Bulk
+// creates are given the ids their service method made or reserved.
+func (i Input) bulkArgs(query string) [][2]string {
+	itemType := "Create" + i.Resource.CamelcaseSingular() + "Params"
+	args := [][2]string{}
+
+	if query == "CreateMany" {
+		args = append(args, [2]string{"ID", "ids"})
+	} else if i.bulkWritesID(query) {
+		args = append(args, [2]string{"ID", fmt.Sprintf("lo.Map(items, func(%s, int) %s { return %s })", itemType, i.ID.GoType(), i.ID.NewIDGo())})
+	}
+
+	for _, field := range i.BulkFields() {
+		value := fmt.Sprintf("lo.Map(items, func(item %s, _ int) %s { return item.%s })", itemType, field.GoType(), field.GoName())
+		if i.IsParentField(field) {
+			value = fmt.Sprintf("lo.Map(items, func(%s, int) %s { return parentID })", itemType, field.GoType())
+		}
+
+		args = append(args, [2]string{sqlcFieldName(field.Name.String()), value})
+	}
+
+	return args
+}
+
+// BulkArgsGo is the Go expression of the args of a bulk create or upsert
+// query. sqlc takes the array of a single column as it is, and those of
+// several in a params struct.
+func (i Input) BulkArgsGo(query string) string {
+	args := i.bulkArgs(query)
+
+	if len(args) == 1 {
+		return args[0][1]
+	}
+
+	lines := lo.Map(args, func(arg [2]string, _ int) string { return "\t\t" + arg[0] + ": " + arg[1] + ",\n" })
+
+	return "dbx." + query + i.Resource.CamelcasePlural() + "Params{\n" + strings.Join(lines, "") + "\t}"
+}
+
+// BulkArgsGoParam declares the args of a bulk create or upsert query in the
+// database interface.
+func (i Input) BulkArgsGoParam(query string) string {
+	args := i.bulkArgs(query)
+
+	if len(args) == 1 {
+		if i.bulkWritesID(query) {
+			return "id []" + i.ID.GoType()
+		}
+
+		field := i.BulkFields()[0]
+
+		return strcase.ToLowerCamel(field.Name.String()) + " []" + field.GoType()
+	}
+
+	return "arg dbx." + query + i.Resource.CamelcasePlural() + "Params"
+}
+
+func bulkHelpersFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "handler", "api", "bulk.go")
+}
+
+// ensureBulkHelpers adds the types and checks the bulk handlers share, the
+// first time a resource with bulk operations is generated.
+func (s *Service) ensureBulkHelpers(input Input) error {
+	path := bulkHelpersFilePath(input.WorkspaceFolder)
+
+	if !input.Bulk || s.fileExists(path) {
+		return nil
+	}
+
+	if err := s.ensureFileExists(path, "bulk_helpers", input); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(filepath.Dir(path), "goimports", "-w", filepath.Base(path)); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
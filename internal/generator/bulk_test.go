@@ -0,0 +1,31 @@
+package generator
+
+import "testing"
+
+func TestBulkColumnsSQL(t *testing.T) {
+	tests := []struct {
+		id         IDStrategy
+		createMany string
+		upsert     string
+	}{
+		{IDStrategyUUID, "id, name", "name"},
+		{IDStrategyBigserial, "id, name", "name"},
+		{IDStrategyUUIDv7, "id, name", "id, name"},
+		{IDStrategyULID, "id, name", "id, name"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.id), func(t *testing.T) {
+			input := testInput(t, "", "Tag", "name:string:unique:not_null")
+			input.ID = test.id
+
+			if columns := input.BulkColumnsSQL("CreateMany"); columns != test.createMany {
+				t.Errorf("expected CreateMany columns %q, got %q", test.createMany, columns)
+			}
+
+			if columns := input.BulkColumnsSQL("Upsert"); columns != test.upsert {
+				t.Errorf("expected Upsert columns %q, got %q", test.upsert, columns)
+			}
+		})
+	}
+}
@@ -0,0 +1,705 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var (
+	ErrResourceNotFound      = errors.New("resource not found")
+	ErrMigrationNotFound     = errors.New("create migration not found")
+	ErrResourceHasDependents = errors.New("other resources depend on it")
+)
+
+// DependentsError lists the resources that would be left pointing at a
+// resource if it were destroyed. They have to be destroyed first.
+type DependentsError struct {
+	Resource   string
+	Dependents []string
+}
+
+func (e *DependentsError) Error() string {
+	return fmt.Sprintf("%s: %v (%s)", e.Resource, ErrResourceHasDependents, strings.Join(e.Dependents, ", "))
+}
+
+func (e *DependentsError) Unwrap() error {
+	return ErrResourceHasDependents
+}
+
+type dropTableTemplateInput struct {
+	Resource   TemplateName
+	EnumTypes  []string
+	JoinTables []string
+}
+
+// Destroy removes everything Generate produced for a resource, and writes a
+// migration that drops its table.
+func (s *Service) Destroy(ctx context.Context, input Input) error {
+	if err := ensureValidResourceName(input.Resource.String()); err != nil {
+		return err
+	}
+
+	s.beginRun()
+
+	input, entry, err := s.resolveInput(input)
+	if err != nil {
+		return err
+	}
+
+	if entry != nil && len(input.Fields) == 0 {
+		input.Fields = entry.Input(input.WorkspaceFolder).Fields
+	}
+
+	if err = s.ensureNoDependents(input); err != nil {
+		return err
+	}
+
+	if err = s.destroy(ctx, input); err != nil {
+		if s.dryRun {
+			return err
+		}
+
+		return s.rollback(input, err)
+	}
+
+	return nil
+}
+
+//nolint:funlen,cyclop
+func (s *Service) destroy(ctx context.Context, input Input) error {
+	presenterPath := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api", "presenter", input.Resource.UnderscoreSingular()+".go")
+	if !s.fileExists(presenterPath) {
+		return fmt.Errorf("%s: %w", input.Resource.String(), ErrResourceNotFound)
+	}
+
+	if len(input.Fields) == 0 {
+		fields, err := s.discoverUpdateableFields(input)
+		if err != nil {
+			return err
+		}
+
+		input.Fields = fields
+	}
+
+	// relations go with the resource, their join tables first
+	relations, err := s.resourceRelations(input)
+	if err != nil {
+		return err
+	}
+
+	if err := s.generateDropTableMigration(ctx, input, relations); err != nil {
+		return fmt.Errorf("failed generating drop table migration: %w", err)
+	}
+
+	for _, relation := range relations {
+		if err := s.removeRelation(relation); err != nil {
+			return fmt.Errorf("failed removing relation %s: %w", relation.JoinTable(), err)
+		}
+	}
+
+	if err := s.removeSQLQueries(
+		filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql"),
+		dbMethodNames(input),
+	); err != nil {
+		return fmt.Errorf("failed removing sql methods: %w", err)
+	}
+
+	if err := s.removeMethodsFromFile(
+		filepath.Join(input.WorkspaceFolder, "internal", "service", "database_iface.go"),
+		dbMethodNames(input),
+	); err != nil {
+		return fmt.Errorf("failed removing methods from database_iface.go: %w", err)
+	}
+
+	if err := s.removeMethodsFromFile(
+		filepath.Join(input.WorkspaceFolder, "internal", input.Service.String()+"_service_iface.go"),
+		serviceMethodNames(input),
+	); err != nil {
+		return fmt.Errorf("failed removing methods from service interface: %w", err)
+	}
+
+	for _, path := range generatedResourceFiles(input) {
+		if err := s.removeFile(path); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range generatedResourceFolders(input) {
+		if err := s.removeFolder(path); err != nil {
+			return err
+		}
+	}
+
+	if err := s.removeRoutes(input); err != nil {
+		return fmt.Errorf("failed removing routes: %w", err)
+	}
+
+	if err := s.removeFrontendInjections(input); err != nil {
+		return fmt.Errorf("failed removing frontend entries: %w", err)
+	}
+
+	if err := s.removeServiceIfEmpty(input); err != nil {
+		return fmt.Errorf("failed removing service: %w", err)
+	}
+
+	if err := s.removeJSONTypes(input); err != nil {
+		return fmt.Errorf("failed removing json types: %w", err)
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "db-migrate"); err != nil {
+		return fmt.Errorf("failed running make db-migrate: %w", err)
+	}
+
+	s.workspace.migrations++
+
+	if err := s.trackFile(filepath.Join(input.WorkspaceFolder, "internal", "database", "schema.sql")); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "db-schema-dump"); err != nil {
+		return fmt.Errorf("failed running make db-schema-dump: %w", err)
+	}
+
+	if err := s.trackFolder(filepath.Join(input.WorkspaceFolder, "internal", "dbx")); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "sqlc-gen"); err != nil {
+		return fmt.Errorf("failed running make sqlc-gen: %w", err)
+	}
+
+	if err := s.forgetResource(input); err != nil {
+		return fmt.Errorf("failed updating manifest: %w", err)
+	}
+
+	if err := s.regenerateParent(ctx, input); err != nil {
+		return fmt.Errorf("failed regenerating parent: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) generateDropTableMigration(_ context.Context, input Input, relations []RelationInput) error {
+	migrationsFolder := filepath.Join(input.WorkspaceFolder, "migrations")
+
+	tableMigrations, err := s.tableMigrations(migrationsFolder, input)
+	if err != nil {
+		return err
+	}
+
+	tableSQL, err := s.readMigrations(tableMigrations)
+	if err != nil {
+		return err
+	}
+
+	joinTables := lo.Map(relations, func(relation RelationInput, _ int) string { return relation.JoinTable() })
+	joinTableMigrations := []string{}
+
+	for _, joinTable := range joinTables {
+		migrations, err := s.globFiles(migrationsFolder, "*_create_"+joinTable+"_table.up.sql")
+		if err != nil {
+			return err
+		}
+
+		if len(migrations) == 0 {
+			return fmt.Errorf("%s: %w", joinTable, ErrMigrationNotFound)
+		}
+
+		joinTableMigrations = append(joinTableMigrations, migrations[len(migrations)-1])
+	}
+
+	joinTableSQL, err := s.readMigrations(joinTableMigrations)
+	if err != nil {
+		return err
+	}
+
+	createSQL := strings.Join(append(tableSQL, joinTableSQL...), "\n")
+
+	upSQL, err := s.renderTemplate("drop_table_up", dropTableTemplateInput{
+		Resource:   input.Resource,
+		EnumTypes:  enumTypesInSQL(strings.Join(tableSQL, "\n")),
+		JoinTables: joinTables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render up template: %w", err)
+	}
+
+	timestamp, err := s.nextMigrationVersion(migrationsFolder)
+	if err != nil {
+		return err
+	}
+
+	if err = s.writeFile(
+		filepath.Join(migrationsFolder, fmt.Sprintf("%s_drop_%s_table.up.sql", timestamp, input.Resource.UnderscorePlural())),
+		upSQL,
+	); err != nil {
+		return fmt.Errorf("failed to create up file: %w", err)
+	}
+
+	// the down migration recreates the table as it is now, and the join tables
+	// of its relations, by replaying the migrations that built them
+	if err = s.writeFile(
+		filepath.Join(migrationsFolder, fmt.Sprintf("%s_drop_%s_table.down.sql", timestamp, input.Resource.UnderscorePlural())),
+		[]byte(createSQL),
+	); err != nil {
+		return fmt.Errorf("failed to create down file: %w", err)
+	}
+
+	return nil
+}
+
+// tableMigrations are the up migrations that built the resource's table as it
+// is now: its latest create migration, and those that added columns to it
+// since. They are found through the workspace, so that the files a dry or
+// forced run wrote count too.
+func (s *Service) tableMigrations(migrationsFolder string, input Input) ([]string, error) {
+	createMigrations, err := s.globFiles(migrationsFolder, "*_create_"+input.Resource.UnderscorePlural()+"_table.up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(createMigrations) == 0 {
+		return nil, fmt.Errorf("%s: %w", input.Resource.UnderscorePlural(), ErrMigrationNotFound)
+	}
+
+	created := createMigrations[len(createMigrations)-1]
+
+	addMigrations, err := s.globFiles(migrationsFolder, "*_add_*_to_"+input.Resource.UnderscorePlural()+".up.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	addMigrations = lo.Filter(addMigrations, func(path string, _ int) bool { return filepath.Base(path) > filepath.Base(created) })
+
+	return append([]string{created}, addMigrations...), nil
+}
+
+// readMigrations reads migrations, each ending in a single newline.
+func (s *Service) readMigrations(paths []string) ([]string, error) {
+	contents := []string{}
+
+	for _, path := range paths {
+		content, err := s.readFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		contents = append(contents, strings.TrimRight(string(content), "\n")+"\n")
+	}
+
+	return contents, nil
+}
+
+// ensureNoDependents refuses to destroy a resource that others are nested
+// under, are children of, or reference, since their code and foreign keys
+// would be left pointing at it. Relations are removed along with it instead.
+func (s *Service) ensureNoDependents(input Input) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	dependents := []string{}
+
+	for _, entry := range manifest.Resources {
+		if entry.Resource == input.Resource.String() {
+			continue
+		}
+
+		if entry.Parent == input.Resource.String() {
+			kind := "child"
+			if entry.Nested {
+				kind = "nested child"
+			}
+
+			dependents = append(dependents, kind+" "+entry.Resource)
+
+			continue
+		}
+
+		for _, field := range entry.Fields {
+			if field.Type == FieldTypeReferences && field.Table == input.Resource.UnderscorePlural() {
+				dependents = append(dependents, entry.Resource+"."+field.Name+" references "+field.Table)
+			}
+		}
+	}
+
+	if len(dependents) > 0 {
+		return &DependentsError{Resource: input.Resource.String(), Dependents: dependents}
+	}
+
+	return nil
+}
+
+func enumTypesInSQL(sql string) []string {
+	matches := regexp.MustCompile(`(?i)CREATE TYPE\s+(\w+)\s+AS ENUM`).FindAllStringSubmatch(sql, -1)
+
+	return lo.Map(matches, func(match []string, _ int) string { return match[1] })
+}
+
+// discoverUpdateableFields recovers the updateable fields of a resource from
+// the update and upload routes that were generated for it.
+func (s *Service) discoverUpdateableFields(input Input) ([]InputField, error) {
+	content, err := s.readFile(filepath.Join(input.WorkspaceFolder, "internal", "route", "api.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	routeRegex := regexp.MustCompile(`"(?:/\w+/:parent_id)?/` + regexp.QuoteMeta(input.Resource.UnderscorePlural()) + `/:id/(update|upload)_(\w+)"`)
+
+	fields := []InputField{}
+
+	for _, match := range routeRegex.FindAllStringSubmatch(string(content), -1) {
+		field := InputField{
+			Service:    input.Service,
+			Resource:   input.Resource,
+			Name:       TemplateName(match[2]),
+			Type:       FieldTypeString,
+			Updateable: true,
+		}
+
+		if match[1] == "upload" {
+			field.Type = FieldTypeAttachment
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func dbMethodNames(input Input) []string {
+	names := []string{
+		"Create" + input.Resource.CamelcaseSingular(),
+		"Delete" + input.Resource.CamelcaseSingular(),
+		"Fetch" + input.Resource.CamelcaseSingular() + "ByID",
+		"Fetch" + input.Resource.CamelcasePlural() + "ByIDs",
+		"Highlight" + input.Resource.CamelcasePlural(),
+		"Restore" + input.Resource.CamelcaseSingular(),
+		"FetchDeleted" + input.Resource.CamelcasePlural(),
+		"FetchDeleted" + input.Resource.CamelcaseSingular() + "IDsBefore",
+		"CountDeleted" + input.Resource.CamelcasePlural(),
+		"PurgeDeleted" + input.Resource.CamelcasePlural(),
+	}
+
+	names = append(names, listingDBMethodNames(input)...)
+	names = append(names, bulkDBMethodNames(input)...)
+
+	if input.Parent != nil {
+		names = append(names, "Delete"+input.Resource.CamelcasePlural()+"By"+input.Parent.CamelcaseSingular()+"IDs")
+	}
+
+	for _, field := range input.Fields {
+		if field.Updateable {
+			names = append(names, "Update"+input.Resource.CamelcaseSingular()+field.Name.CamelcaseSingular())
+		}
+
+		if field.Array {
+			names = append(names,
+				"Fetch"+input.Resource.CamelcasePlural()+"With"+field.Name.CamelcaseSingular(),
+				"Count"+input.Resource.CamelcasePlural()+"With"+field.Name.CamelcaseSingular(),
+			)
+		}
+
+		if field.HasMany() {
+			names = append(names,
+				"Fetch"+input.Resource.CamelcasePlural()+"For"+field.ReferenceName().CamelcaseSingular(),
+				"Count"+input.Resource.CamelcasePlural()+"For"+field.ReferenceName().CamelcaseSingular(),
+			)
+		}
+	}
+
+	return names
+}
+
+// listingDBMethodNames are the queries of the resource's listings, which
+// filter and sort by its fields.
+func listingDBMethodNames(input Input) []string {
+	return []string{
+		"CountRecent" + input.Resource.CamelcasePlural(),
+		"CountSearched" + input.Resource.CamelcasePlural(),
+		"FetchRecent" + input.Resource.CamelcasePlural(),
+		"FetchRecent" + input.Resource.CamelcasePlural() + "Before",
+		"Search" + input.Resource.CamelcasePlural(),
+		"Search" + input.Resource.CamelcasePlural() + "Before",
+	}
+}
+
+// bulkDBMethodNames are the queries a resource with bulk operations writes
+// and destroys its rows in bulk with.
+func bulkDBMethodNames(input Input) []string {
+	return []string{
+		"Reserve" + input.Resource.CamelcaseSingular() + "IDs",
+		"CreateMany" + input.Resource.CamelcasePlural(),
+		"Upsert" + input.Resource.CamelcasePlural(),
+		"DeleteMany" + input.Resource.CamelcasePlural(),
+	}
+}
+
+func serviceMethodNames(input Input) []string {
+	names := []string{
+		"Create" + input.Resource.CamelcaseSingular(),
+		"Search" + input.Resource.CamelcasePlural(),
+		"Highlight" + input.Resource.CamelcasePlural(),
+		"FetchRecent" + input.Resource.CamelcasePlural(),
+		"Fetch" + input.Resource.CamelcaseSingular(),
+		"Destroy" + input.Resource.CamelcaseSingular(),
+		"Restore" + input.Resource.CamelcaseSingular(),
+		"FetchDeleted" + input.Resource.CamelcasePlural(),
+		"PurgeDeleted" + input.Resource.CamelcasePlural(),
+		"CreateMany" + input.Resource.CamelcasePlural(),
+		"Upsert" + input.Resource.CamelcasePlural(),
+		"DestroyMany" + input.Resource.CamelcasePlural(),
+	}
+
+	for _, field := range input.Fields {
+		if field.Array {
+			names = append(names, "Fetch"+input.Resource.CamelcasePlural()+"With"+field.Name.CamelcaseSingular())
+		}
+
+		if field.HasMany() {
+			names = append(names, "Fetch"+input.Resource.CamelcasePlural()+"For"+field.ReferenceName().CamelcaseSingular())
+		}
+
+		if field.Type == FieldTypeReferences {
+			names = append(names, "Fetch"+field.ReferenceName().CamelcasePlural()+"For"+input.Resource.CamelcasePlural())
+		}
+
+		if !field.Updateable {
+			continue
+		}
+
+		if field.Type == FieldTypeAttachment {
+			names = append(names, "Upload"+input.Resource.CamelcaseSingular()+field.Name.CamelcaseSingular())
+		} else {
+			names = append(names, "Update"+input.Resource.CamelcaseSingular()+field.Name.CamelcaseSingular())
+		}
+	}
+
+	return names
+}
+
+func handlerNames(input Input) []string {
+	names := []string{
+		input.Resource.CamelcasePlural() + "Create",
+		input.Resource.CamelcasePlural() + "Search",
+		input.Resource.CamelcasePlural() + "FetchRecent",
+		input.Resource.CamelcasePlural() + "Show",
+		input.Resource.CamelcasePlural() + "Destroy",
+		input.Resource.CamelcasePlural() + "Restore",
+		input.Resource.CamelcasePlural() + "FetchDeleted",
+		input.Resource.CamelcasePlural() + "Bulk",
+	}
+
+	for _, field := range input.Fields {
+		if field.HasMany() {
+			names = append(names, input.Resource.CamelcasePlural()+"FetchFor"+field.ReferenceName().CamelcaseSingular())
+		}
+
+		if !field.Updateable {
+			continue
+		}
+
+		if field.Type == FieldTypeAttachment {
+			names = append(names, input.Resource.CamelcasePlural()+"Upload"+field.Name.CamelcaseSingular())
+		} else {
+			names = append(names, input.Resource.CamelcasePlural()+"Update"+field.Name.CamelcaseSingular())
+		}
+	}
+
+	return names
+}
+
+//nolint:funlen
+func generatedResourceFiles(input Input) []string {
+	serviceFolder := filepath.Join(input.WorkspaceFolder, "internal", "service", input.Service.String())
+	handlerFolder := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api")
+
+	files := []string{
+		filepath.Join(serviceFolder, "create_"+input.Resource.UnderscoreSingular()+".go"),
+		filepath.Join(serviceFolder, "fetch_recent_"+input.Resource.UnderscorePlural()+".go"),
+		filepath.Join(serviceFolder, "fetch_"+input.Resource.UnderscoreSingular()+".go"),
+		filepath.Join(serviceFolder, "destroy_"+input.Resource.UnderscoreSingular()+".go"),
+		filepath.Join(serviceFolder, "search_"+input.Resource.UnderscorePlural()+".go"),
+		filepath.Join(serviceFolder, "highlight_"+input.Resource.UnderscorePlural()+".go"),
+		filepath.Join(serviceFolder, "restore_"+input.Resource.UnderscoreSingular()+".go"),
+		filepath.Join(serviceFolder, "fetch_deleted_"+input.Resource.UnderscorePlural()+".go"),
+		filepath.Join(serviceFolder, "purge_deleted_"+input.Resource.UnderscorePlural()+".go"),
+		filepath.Join(serviceFolder, "bulk_"+input.Resource.UnderscorePlural()+".go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_create.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_fetch_recent.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_show.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_destroy.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_search.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_restore.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_fetch_deleted.go"),
+		filepath.Join(handlerFolder, input.Resource.UnderscorePlural()+"_bulk.go"),
+		filepath.Join(handlerFolder, "presenter", input.Resource.UnderscoreSingular()+".go"),
+		filepath.Join(input.WorkspaceFolder, "frontend", "src", "models", input.Resource.CamelcaseSingular()+".ts"),
+		filepath.Join(input.WorkspaceFolder, "frontend", "src", "slices", input.Resource.CamelcaseSingular()+".ts"),
+	}
+
+	for _, field := range input.Fields {
+		if field.Array {
+			files = append(files, filepath.Join(serviceFolder, fmt.Sprintf("fetch_%s_with_%s.go", input.Resource.UnderscorePlural(), field.Name.UnderscoreSingular())))
+		}
+
+		if field.HasMany() {
+			files = append(
+				files,
+				filepath.Join(serviceFolder, fmt.Sprintf("fetch_%s_for_%s.go", input.Resource.UnderscorePlural(), field.ReferenceName().UnderscoreSingular())),
+				filepath.Join(handlerFolder, fmt.Sprintf("%s_fetch_for_%s.go", input.Resource.UnderscorePlural(), field.ReferenceName().UnderscoreSingular())),
+			)
+		}
+
+		if field.Type == FieldTypeReferences {
+			files = append(files, filepath.Join(serviceFolder, fmt.Sprintf("fetch_%s_for_%s.go", field.ReferenceName().UnderscorePlural(), input.Resource.UnderscorePlural())))
+		}
+
+		if !field.Updateable {
+			continue
+		}
+
+		action := "update"
+		if field.Type == FieldTypeAttachment {
+			action = "upload"
+		}
+
+		files = append(
+			files,
+			filepath.Join(serviceFolder, fmt.Sprintf("%s_%s_%s.go", action, input.Resource.UnderscoreSingular(), field.Name.UnderscoreSingular())),
+			filepath.Join(handlerFolder, fmt.Sprintf("%s_%s_%s.go", input.Resource.UnderscorePlural(), action, field.Name.UnderscoreSingular())),
+		)
+	}
+
+	return files
+}
+
+func generatedResourceFolders(input Input) []string {
+	componentsFolder := filepath.Join(input.WorkspaceFolder, "frontend", "src", "components")
+
+	return []string{
+		filepath.Join(componentsFolder, input.Resource.CamelcasePlural()+"Page"),
+		filepath.Join(componentsFolder, input.Resource.CamelcaseSingular()+"Page"),
+	}
+}
+
+// removeMethodsFromFile removes single-line method declarations, as written
+// by goimports into an interface, for each of the given method names.
+func (s *Service) removeMethodsFromFile(filePath string, names []string) error {
+	if !s.fileExists(filePath) {
+		return nil
+	}
+
+	if err := s.removeLinesFromFile(filePath, func(line string) bool {
+		trimmed := strings.TrimSpace(line)
+
+		return lo.SomeBy(names, func(name string) bool { return strings.HasPrefix(trimmed, name+"(") })
+	}); err != nil {
+		return err
+	}
+
+	return s.runCommand(filepath.Dir(filePath), "goimports", "-w", filepath.Base(filePath))
+}
+
+func (s *Service) removeRoutes(input Input) error {
+	folderPath := filepath.Join(input.WorkspaceFolder, "internal", "route")
+	handlers := lo.Map(handlerNames(input), func(name string, _ int) string { return "api." + name + "(services)" })
+
+	if err := s.removeLinesFromFile(filepath.Join(folderPath, "api.go"), func(line string) bool {
+		return lo.SomeBy(handlers, func(handler string) bool { return strings.Contains(line, handler) })
+	}); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(folderPath, "goimports", "-w", "api.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	if err := s.removeInjectedTemplate(filepath.Join(folderPath, "setup.go"), "route_setup", input); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(folderPath, "goimports", "-w", "setup.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) removeFrontendInjections(input Input) error {
+	storePath := filepath.Join(input.WorkspaceFolder, "frontend", "src", "store", "index.ts")
+	appPath := filepath.Join(input.WorkspaceFolder, "frontend", "src", "components", "App", "index.tsx")
+
+	injections := []struct {
+		path string
+		name string
+	}{
+		{storePath, "frontend_store_import"},
+		{storePath, "frontend_store_reducer"},
+		{storePath, "frontend_store_middleware"},
+		{appPath, "frontend_app_import"},
+		{appPath, "frontend_app_route"},
+	}
+
+	for _, injection := range injections {
+		if err := s.removeInjectedTemplate(injection.path, injection.name, input); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", injection.name, err)
+		}
+	}
+
+	return nil
+}
+
+// removeServiceIfEmpty removes the service itself once its last resource is
+// gone: the service folder, its interface, and its entries in services.go
+// and main.go.
+func (s *Service) removeServiceIfEmpty(input Input) error {
+	serviceFolder := filepath.Join(input.WorkspaceFolder, "internal", "service", input.Service.String())
+
+	remaining, err := filepath.Glob(filepath.Join(serviceFolder, "*.go"))
+	if err != nil {
+		return fmt.Errorf("failed to list service folder: %w", err)
+	}
+
+	remaining = lo.Filter(remaining, func(path string, _ int) bool { return s.fileExists(path) })
+	if len(remaining) != 1 || filepath.Base(remaining[0]) != "service.go" {
+		return nil
+	}
+
+	if err = s.removeFolder(serviceFolder); err != nil {
+		return err
+	}
+
+	if err = s.removeFile(filepath.Join(input.WorkspaceFolder, "internal", input.Service.String()+"_service_iface.go")); err != nil {
+		return err
+	}
+
+	servicesField := input.Service.Capitalize() + " " + input.Service.Capitalize() + "Service"
+
+	if err = s.removeLinesFromFile(filepath.Join(input.WorkspaceFolder, "internal", "services.go"), func(line string) bool {
+		return strings.Join(strings.Fields(line), " ") == servicesField
+	}); err != nil {
+		return err
+	}
+
+	if err = s.runCommand(filepath.Join(input.WorkspaceFolder, "internal"), "goimports", "-w", "services.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	if err = s.removeInjectedTemplate(filepath.Join(input.WorkspaceFolder, "main.go"), "main_service_init", input); err != nil {
+		return err
+	}
+
+	if err = s.runCommand(input.WorkspaceFolder, "goimports", "-w", "main.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDropTableMigrationRecreatesAddedColumns(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Post", "title:string")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	if err := New().AddFields(context.Background(), testInput(t, workspaceFolder, "Post", "status:enum:values=draft,published:default=draft")); err != nil {
+		t.Fatalf("failed to add fields: %v", err)
+	}
+
+	if err := New().Destroy(context.Background(), testInput(t, workspaceFolder, "Post")); err != nil {
+		t.Fatalf("failed to destroy: %v", err)
+	}
+
+	downMigrations, err := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*_drop_posts_table.down.sql"))
+	if err != nil || len(downMigrations) != 1 {
+		t.Fatalf("expected a drop migration, got %v: %v", downMigrations, err)
+	}
+
+	downSQL := readTestFile(t, downMigrations[0])
+
+	for _, statement := range []string{"CREATE TABLE posts", "ADD COLUMN status"} {
+		if !strings.Contains(downSQL, statement) {
+			t.Errorf("expected the down migration to have %q, got:\n%s", statement, downSQL)
+		}
+	}
+
+	upMigrations, _ := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*_drop_posts_table.up.sql"))
+	if upSQL := readTestFile(t, upMigrations[0]); !strings.Contains(upSQL, "DROP TYPE") {
+		t.Errorf("expected the up migration to drop the added enum type, got:\n%s", upSQL)
+	}
+}
+
+func TestDestroyRefusesDependentsAndRemovesRelations(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	post := testInput(t, workspaceFolder, "Post", "title:string")
+	if err := New().Generate(context.Background(), post); err != nil {
+		t.Fatalf("failed to generate post: %v", err)
+	}
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Tag", "name:string")); err != nil {
+		t.Fatalf("failed to generate tag: %v", err)
+	}
+
+	if err := New().ManyToMany(context.Background(), RelationInput{WorkspaceFolder: workspaceFolder, Left: "Post", Right: "Tag"}); err != nil {
+		t.Fatalf("failed to relate: %v", err)
+	}
+
+	comment := testInput(t, workspaceFolder, "Comment", "body:string", "post_id:references:table=posts:not_null")
+	comment.Parent = &post.Resource
+	comment.Nested = true
+
+	if err := New().Generate(context.Background(), comment); err != nil {
+		t.Fatalf("failed to generate comment: %v", err)
+	}
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Like", "post_id:references:table=posts")); err != nil {
+		t.Fatalf("failed to generate like: %v", err)
+	}
+
+	err := New().Destroy(context.Background(), testInput(t, workspaceFolder, "Post"))
+	if !errors.Is(err, ErrResourceHasDependents) {
+		t.Fatalf("expected %v, got %v", ErrResourceHasDependents, err)
+	}
+
+	for _, dependent := range []string{"nested child Comment", "Like.post_id references posts"} {
+		if !strings.Contains(err.Error(), dependent) {
+			t.Errorf("expected the error to list %q, got %v", dependent, err)
+		}
+	}
+
+	if _, err = os.Stat(filepath.Join(workspaceFolder, "internal", "handler", "api", "presenter", "post.go")); err != nil {
+		t.Errorf("expected the refused destroy to leave Post alone: %v", err)
+	}
+
+	for _, dependent := range []string{"Comment", "Like"} {
+		if err = New().Destroy(context.Background(), testInput(t, workspaceFolder, dependent)); err != nil {
+			t.Fatalf("failed to destroy %s: %v", dependent, err)
+		}
+	}
+
+	if err = New().Destroy(context.Background(), testInput(t, workspaceFolder, "Post")); err != nil {
+		t.Fatalf("failed to destroy post: %v", err)
+	}
+
+	leftovers := []string{"AttachTagToPost", "AttachPostToTag", "TagsFetchPosts", "PostsFetchTags", "useFetchPostsQuery"}
+
+	err = filepath.WalkDir(workspaceFolder, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.Contains(path, string(filepath.Separator)+".oxgen"+string(filepath.Separator)) {
+			return err
+		}
+
+		content := readTestFile(t, path)
+
+		for _, leftover := range leftovers {
+			if strings.Contains(content, leftover) {
+				t.Errorf("expected %s to be removed from %s", leftover, path)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk workspace: %v", err)
+	}
+
+	manifest, err := New().LoadManifest(workspaceFolder)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+
+	if len(manifest.Relations) != 0 {
+		t.Errorf("expected the relation to be forgotten, got %v", manifest.Relations)
+	}
+
+	upMigrations, _ := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*_drop_posts_table.up.sql"))
+	if upSQL := readTestFile(t, upMigrations[0]); !strings.HasPrefix(upSQL, "DROP TABLE posts_tags;\nDROP TABLE posts;") {
+		t.Errorf("expected the join table to be dropped first, got:\n%s", upSQL)
+	}
+
+	downMigrations, _ := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*_drop_posts_table.down.sql"))
+	if downSQL := readTestFile(t, downMigrations[0]); !strings.Contains(downSQL, "CREATE TABLE posts_tags") {
+		t.Errorf("expected the down migration to recreate the join table, got:\n%s", downSQL)
+	}
+}
@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// WriteDryRunReport prints a unified diff for every file the dry run would
+// have touched, followed by the external commands it would have run.
+func (s *Service) WriteDryRunReport(w io.Writer) error {
+	for _, path := range s.workspace.order {
+		original := s.workspace.originals[path]
+
+		fromFile := diffLabel("a", path)
+		if !original.existed {
+			fromFile = "/dev/null"
+		}
+
+		toFile := diffLabel("b", path)
+		if s.workspace.deleted[path] {
+			toFile = "/dev/null"
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(original.content),
+			B:        splitLines(s.workspace.overlay[path]),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3, //nolint:gomnd
+		})
+		if err != nil {
+			return fmt.Errorf("failed to diff %s: %w", path, err)
+		}
+
+		if diff == "" {
+			continue
+		}
+
+		if _, err = io.WriteString(w, diff); err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+
+		if !strings.HasSuffix(diff, "\n") {
+			if _, err = io.WriteString(w, "\n"); err != nil {
+				return fmt.Errorf("failed to write diff: %w", err)
+			}
+		}
+	}
+
+	if len(s.workspace.commands) == 0 {
+		return nil
+	}
+
+	if _, err := io.WriteString(w, "\nCommands that would run:\n"); err != nil {
+		return fmt.Errorf("failed to write commands: %w", err)
+	}
+
+	for _, command := range s.workspace.commands {
+		if _, err := fmt.Fprintf(w, "  %s\n", command); err != nil {
+			return fmt.Errorf("failed to write commands: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func diffLabel(prefix string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return prefix + "/" + filepath.ToSlash(path)
+}
+
+func splitLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+
+	return difflib.SplitLines(string(content))
+}
@@ -2,9 +2,7 @@ package generator
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 )
 
@@ -12,39 +10,17 @@ type ServiceTemplateInput struct {
 	Service TemplateName
 }
 
-const serviceTemplate = `package {{ .Service.Downcase }}
-
-func New(storageFolder string, dbx service.DatabaseProvider) *Service {
-  return &Service{
-    storageFolder: storageFolder,
-    dbx: dbx,
-  }
-}
-
-type Service struct {
-  storageFolder string
-  dbx service.DatabaseProvider
-}
-`
-
-const mainServiceInitTemplate = `{{ .Service.String }}Service := {{ .Service.String }}.New(cfg.StorageFolder(), db)
-services.{{ .Service.Capitalize }} = {{ .Service.String }}Service
-`
-
 func (s *Service) ensureServiceExists(ctx context.Context, input Input) error {
 	folderPath := filepath.Join(input.WorkspaceFolder, "internal", "service", input.Service.String())
 	filePath := filepath.Join(folderPath, "service.go")
 
-	serviceFileExists := true
-	if _, err := os.Stat(filePath); errors.Is(err, os.ErrNotExist) {
-		serviceFileExists = false
-	}
+	serviceFileExists := s.fileExists(filePath)
 
 	if err := s.ensureFolderExists(folderPath); err != nil {
 		return fmt.Errorf("failed to ensure service folder exists: %w", err)
 	}
 
-	if err := s.ensureFileExists(filePath, input.Service.String(), serviceTemplate, input); err != nil {
+	if err := s.ensureFileExists(filePath, "service_base", input); err != nil {
 		return fmt.Errorf("failed to ensure service file exists: %w", err)
 	}
 
@@ -62,8 +38,7 @@ func (s *Service) ensureServiceExists(ctx context.Context, input Input) error {
 		if err := s.injectTemplateAboveLine(
 			filepath.Join(input.WorkspaceFolder, "main.go"),
 			"// End of main code generated by oxgen. DO NOT EDIT.",
-			"main-service",
-			mainServiceInitTemplate,
+			"main_service_init",
 			input,
 		); err != nil {
 			return fmt.Errorf("failed to inject service init into main: %w", err)
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var ErrResourceExists = errors.New("resource already exists")
+
+// ExistingResourceError is returned by Generate when parts of the resource
+// are already in the project, before anything is written.
+type ExistingResourceError struct {
+	Resource  string
+	Artifacts []string
+}
+
+func (e *ExistingResourceError) Error() string {
+	return fmt.Sprintf("%s: %v (found %s)", e.Resource, ErrResourceExists, strings.Join(e.Artifacts, ", "))
+}
+
+func (e *ExistingResourceError) Unwrap() error {
+	return ErrResourceExists
+}
+
+// existingArtifacts lists the parts of the resource that are already in the
+// project: its table in schema.sql, its queries in queries.sql, its methods
+// on DatabaseProvider and its routes in api.go.
+func (s *Service) existingArtifacts(input Input) ([]string, error) {
+	artifacts := []string{}
+
+	schemaPath := filepath.Join(input.WorkspaceFolder, "internal", "database", "schema.sql")
+
+	if s.fileExists(schemaPath) {
+		content, err := s.readFile(schemaPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if tableDefined(content, input.Resource.UnderscorePlural()) {
+			artifacts = append(artifacts, "table "+input.Resource.UnderscorePlural())
+		}
+	}
+
+	queriesPath := filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql")
+
+	if s.fileExists(queriesPath) {
+		lines, err := s.readLines(queriesPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range lines {
+			if name, found := sqlQueryName(line); found && lo.Contains(dbMethodNames(input), name) {
+				artifacts = append(artifacts, "query "+name)
+			}
+		}
+	}
+
+	ifacePath := filepath.Join(input.WorkspaceFolder, "internal", "service", "database_iface.go")
+
+	if s.fileExists(ifacePath) {
+		methods, err := s.interfaceMethods(ifacePath, "DatabaseProvider")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, method := range lo.Intersect(dbMethodNames(input), methods) {
+			artifacts = append(artifacts, "DatabaseProvider."+method)
+		}
+	}
+
+	routesPath := filepath.Join(input.WorkspaceFolder, "internal", "route", "api.go")
+
+	if s.fileExists(routesPath) {
+		content, err := s.readFile(routesPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, handler := range handlerNames(input) {
+			if strings.Contains(string(content), "api."+handler+"(services)") {
+				artifacts = append(artifacts, "route api."+handler)
+			}
+		}
+	}
+
+	return artifacts, nil
+}
+
+// tableDefined reports whether a schema dump creates the named table.
+func tableDefined(schema []byte, table string) bool {
+	tableRegex := regexp.MustCompile(`(?i)CREATE TABLE (IF NOT EXISTS )?(public\.)?"?` + regexp.QuoteMeta(table) + `"?\s*\(`)
+
+	return tableRegex.Match(schema)
+}
+
+// interfaceMethods lists the methods of the named interface type in a Go file.
+func (s *Service) interfaceMethods(filePath string, name string) ([]string, error) {
+	content, err := s.readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, content, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	methods := []string{}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		typeSpec, isTypeSpec := node.(*ast.TypeSpec)
+		if !isTypeSpec || typeSpec.Name.Name != name {
+			return true
+		}
+
+		if iface, isInterface := typeSpec.Type.(*ast.InterfaceType); isInterface {
+			for _, method := range iface.Methods.List {
+				for _, methodName := range method.Names {
+					methods = append(methods, methodName.Name)
+				}
+			}
+		}
+
+		return false
+	})
+
+	return methods, nil
+}
+
+// clearExisting removes what is already in the project for the resource, so
+// that it can be generated again from scratch. A resource that was generated
+// in full is destroyed, dropping its table; leftovers of a partial run only
+// have their queries, methods and routes removed.
+func (s *Service) clearExisting(ctx context.Context, input Input) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	existing := input
+	existing.Fields = nil
+
+	if entry, found := manifest.Find(input.Resource.String()); found {
+		existing = entry.Input(input.WorkspaceFolder)
+	}
+
+	presenterPath := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api", "presenter", input.Resource.UnderscoreSingular()+".go")
+	if s.fileExists(presenterPath) {
+		return s.destroy(ctx, existing)
+	}
+
+	if existing.Fields == nil {
+		if existing.Fields, err = s.discoverUpdateableFields(existing); err != nil {
+			return err
+		}
+	}
+
+	if err = s.removeSQLQueries(
+		filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql"),
+		dbMethodNames(existing),
+	); err != nil {
+		return fmt.Errorf("failed removing sql methods: %w", err)
+	}
+
+	if err = s.removeMethodsFromFile(
+		filepath.Join(input.WorkspaceFolder, "internal", "service", "database_iface.go"),
+		dbMethodNames(existing),
+	); err != nil {
+		return fmt.Errorf("failed removing methods from database_iface.go: %w", err)
+	}
+
+	return s.removeRoutes(existing)
+}
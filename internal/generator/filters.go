@@ -0,0 +1,381 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// maxSortKeys is how many columns a listing can be sorted by. The ORDER BY of
+// its queries has a CASE for every sortable column at every position.
+const maxSortKeys = 2
+
+// FilterOp is how a filter[...] param compares a column with its value.
+type FilterOp string
+
+const (
+	FilterOpEq  FilterOp = "eq"
+	FilterOpIn  FilterOp = "in"
+	FilterOpGte FilterOp = "gte"
+	FilterOpLte FilterOp = "lte"
+)
+
+// ListFilter is a filter[...] param of the recent and search listings. Its
+// value is sent to Postgres as text and cast to the column's type there, so
+// the query only ever compares whitelisted columns with bound values, and a
+// value the column can't hold fails it with a data exception.
+type ListFilter struct {
+	Column  string
+	SQLType string
+	Op      FilterOp
+	// TypescriptType is the type of the value in the frontend slice.
+	TypescriptType string
+}
+
+// Key is the param's name inside filter[], as the handlers and the service's
+// Filters know it: title for filter[title], views[gte] for filter[views][gte].
+func (f ListFilter) Key() string {
+	if f.Op == FilterOpEq {
+		return f.Column
+	}
+
+	return f.Column + "[" + string(f.Op) + "]"
+}
+
+func (f ListFilter) paramName() string {
+	if f.Op == FilterOpEq {
+		return "filter_" + f.Column
+	}
+
+	return "filter_" + f.Column + "_" + string(f.Op)
+}
+
+// PredicateSQL is the WHERE condition of the filter, which holds for every
+// row when the filter isn't set.
+func (f ListFilter) PredicateSQL() string {
+	param := "sqlc.narg('" + f.paramName() + "')"
+
+	if f.Op == FilterOpIn {
+		cast := ""
+		if f.SQLType != "text" {
+			cast = "::" + f.SQLType + "[]"
+		}
+
+		return fmt.Sprintf("(%s::text[] IS NULL OR t.%s = ANY(%s::text[]%s))", param, f.Column, param, cast)
+	}
+
+	cast := ""
+	if f.SQLType != "text" {
+		cast = "::" + f.SQLType
+	}
+
+	operator := map[FilterOp]string{FilterOpEq: "=", FilterOpGte: ">=", FilterOpLte: "<="}[f.Op]
+
+	return fmt.Sprintf("(%s::text IS NULL OR t.%s %s %s::text%s)", param, f.Column, operator, param, cast)
+}
+
+// GoAssignment sets the filter's field of the dbx params from the service's
+// Filters.
+func (f ListFilter) GoAssignment() string {
+	if f.Op == FilterOpIn {
+		return sqlcFieldName(f.paramName()) + ": filters.Values(\"" + f.Key() + "\"),"
+	}
+
+	return sqlcFieldName(f.paramName()) + ": filters.Value(\"" + f.Key() + "\"),"
+}
+
+// sqlcFieldName is the name sqlc gives the params struct field of a query
+// param, with id spelt ID as its default initialisms have it.
+func sqlcFieldName(param string) string {
+	words := strings.Split(param, "_")
+
+	for i, word := range words {
+		if word == "id" {
+			words[i] = "ID"
+
+			continue
+		}
+
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+
+	return strings.Join(words, "")
+}
+
+// filterOps are the ways a field can be filtered by. Ranges make sense for
+// numbers and dates, equality for anything that can be compared exactly.
+func (f InputField) filterOps() []FilterOp {
+	if f.Array {
+		return nil
+	}
+
+	switch f.Type {
+	case FieldTypeString, FieldTypeEnum, FieldTypeUUID, FieldTypeReferences:
+		return []FilterOp{FilterOpEq, FilterOpIn}
+	case FieldTypeInt, FieldTypeBigint:
+		return []FilterOp{FilterOpEq, FilterOpIn, FilterOpGte, FilterOpLte}
+	case FieldTypeFloat, FieldTypeDecimal, FieldTypeTimestamp:
+		return []FilterOp{FilterOpGte, FilterOpLte}
+	case FieldTypeDate:
+		return []FilterOp{FilterOpEq, FilterOpGte, FilterOpLte}
+	case FieldTypeBool:
+		return []FilterOp{FilterOpEq}
+	case FieldTypeJSON, FieldTypeAttachment, FieldTypeUnknown:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Sortable fields are the ones with a meaningful order.
+func (f InputField) Sortable() bool {
+	if f.Array {
+		return false
+	}
+
+	switch f.Type {
+	case FieldTypeString, FieldTypeEnum, FieldTypeInt, FieldTypeBigint, FieldTypeFloat, FieldTypeDecimal,
+		FieldTypeBool, FieldTypeDate, FieldTypeTimestamp:
+		return true
+	case FieldTypeJSON, FieldTypeUUID, FieldTypeReferences, FieldTypeAttachment, FieldTypeUnknown:
+		return false
+	default:
+		return false
+	}
+}
+
+// filterTypescriptType is the type of a filter value in the frontend slice.
+// Dates are sent as they are written in the query string.
+func (f InputField) filterTypescriptType() string {
+	switch f.Type {
+	case FieldTypeEnum:
+		return "NonNullable<" + f.Resource.CamelcaseSingular() + "['" + f.FrontendName() + "']>"
+	case FieldTypeDate, FieldTypeTimestamp:
+		return "string"
+	default:
+		return f.elementTypescriptType()
+	}
+}
+
+// ListFilters are the filters the recent and search listings take: those of
+// the resource's fields, then ranges of its timestamps. The parent key of a
+// nested resource is left out, since its listings are always of one parent.
+func (i Input) ListFilters() []ListFilter {
+	filters := i.fieldListFilters()
+
+	for _, column := range []string{"created_at", "updated_at"} {
+		for _, op := range []FilterOp{FilterOpGte, FilterOpLte} {
+			filters = append(filters, ListFilter{Column: column, SQLType: "timestamp", Op: op, TypescriptType: "string"})
+		}
+	}
+
+	return filters
+}
+
+// fieldListFilters are the filters of the resource's fields alone.
+func (i Input) fieldListFilters() []ListFilter {
+	filters := []ListFilter{}
+
+	for _, field := range i.Fields {
+		if i.IsParentField(field) {
+			continue
+		}
+
+		for _, op := range field.filterOps() {
+			filters = append(filters, ListFilter{
+				Column:         field.Name.String(),
+				SQLType:        field.ElementSQLType(),
+				Op:             op,
+				TypescriptType: field.filterTypescriptType(),
+			})
+		}
+	}
+
+	return filters
+}
+
+// ListFiltersSQL are the WHERE conditions of the listing queries' filters.
+func (i Input) ListFiltersSQL() string {
+	predicates := []string{}
+
+	for _, filter := range i.ListFilters() {
+		predicates = append(predicates, filter.PredicateSQL())
+	}
+
+	return strings.Join(predicates, "\n    AND ")
+}
+
+// SortKeys are the columns the offset paginated listings can be sorted by.
+func (i Input) SortKeys() []string {
+	return append(i.fieldSortKeys(), "created_at", "updated_at")
+}
+
+// fieldSortKeys are the sort keys of the resource's fields alone.
+func (i Input) fieldSortKeys() []string {
+	keys := []string{}
+
+	for _, field := range i.Fields {
+		if field.Sortable() && !i.IsParentField(field) {
+			keys = append(keys, field.Name.String())
+		}
+	}
+
+	return keys
+}
+
+// extendsListings is true if the listings can be filtered or sorted by any
+// of the fields, so that adding them changes their whitelists.
+func (i Input) extendsListings() bool {
+	return len(i.fieldListFilters()) > 0 || len(i.fieldSortKeys()) > 0
+}
+
+// SortSQL are the ORDER BY terms sorting by the @sort_keys of a listing,
+// ahead of its default order. A key picks its column at its position, in
+// descending order when it starts with a minus. Unset positions match no
+// CASE and leave the order alone.
+func (i Input) SortSQL() string {
+	terms := []string{}
+
+	for position := 1; position <= maxSortKeys; position++ {
+		for _, key := range i.SortKeys() {
+			terms = append(terms,
+				fmt.Sprintf("CASE WHEN (@sort_keys::text[])[%d] = '%s' THEN t.%s END ASC", position, key, key),
+				fmt.Sprintf("CASE WHEN (@sort_keys::text[])[%d] = '-%s' THEN t.%s END DESC", position, key, key),
+			)
+		}
+	}
+
+	return strings.Join(terms, ",\n    ")
+}
+
+// ListFilterKeysGo is the Go slice literal of the filter keys the handlers
+// accept.
+func (i Input) ListFilterKeysGo() string {
+	keys := []string{}
+
+	for _, filter := range i.ListFilters() {
+		keys = append(keys, fmt.Sprintf("%q", filter.Key()))
+	}
+
+	return "[]string{" + strings.Join(keys, ", ") + "}"
+}
+
+// SortKeysGo is the Go slice literal of the sort keys the handlers accept.
+func (i Input) SortKeysGo() string {
+	keys := []string{}
+
+	for _, key := range i.SortKeys() {
+		keys = append(keys, fmt.Sprintf("%q", key))
+	}
+
+	return "[]string{" + strings.Join(keys, ", ") + "}"
+}
+
+// FrontendFilterDeclarations declare the columns of the slice's Filters
+// interface. A column is given its value when filtered by equality alone,
+// and an object of its operators otherwise, where eq stands for the bare
+// filter[column] param.
+func (i Input) FrontendFilterDeclarations() []string {
+	return frontendFilterDeclarations(i.ListFilters())
+}
+
+// FrontendFieldFilterDeclarations declare the columns of the resource's
+// fields alone, for adding them to an existing slice.
+func (i Input) FrontendFieldFilterDeclarations() []string {
+	return frontendFilterDeclarations(i.fieldListFilters())
+}
+
+func frontendFilterDeclarations(listFilters []ListFilter) []string {
+	declarations := []string{}
+	ops := map[string][]ListFilter{}
+	columns := []string{}
+
+	for _, filter := range listFilters {
+		if _, found := ops[filter.Column]; !found {
+			columns = append(columns, filter.Column)
+		}
+
+		ops[filter.Column] = append(ops[filter.Column], filter)
+	}
+
+	for _, column := range columns {
+		filters := ops[column]
+
+		if len(filters) == 1 && filters[0].Op == FilterOpEq {
+			declarations = append(declarations, column+"?: "+filters[0].TypescriptType+";")
+
+			continue
+		}
+
+		members := []string{}
+
+		for _, filter := range filters {
+			valueType := filter.TypescriptType
+			if filter.Op == FilterOpIn {
+				valueType += "[]"
+			}
+
+			members = append(members, string(filter.Op)+"?: "+valueType+";")
+		}
+
+		declarations = append(declarations, column+"?: { "+strings.Join(members, " ")+" };")
+	}
+
+	return declarations
+}
+
+// FrontendSortKeys are the members of the union of the sort keys of the
+// slice's listings.
+func (i Input) FrontendSortKeys() []string {
+	return frontendSortKeys(i.SortKeys())
+}
+
+// FrontendFieldSortKeys are the members for the resource's fields alone, for
+// adding them to an existing slice.
+func (i Input) FrontendFieldSortKeys() []string {
+	return frontendSortKeys(i.fieldSortKeys())
+}
+
+func frontendSortKeys(sortKeys []string) []string {
+	keys := []string{}
+
+	for _, key := range sortKeys {
+		keys = append(keys, sqlQuote(key), sqlQuote("-"+key))
+	}
+
+	return keys
+}
+
+func listFiltersFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "service", "filters.go")
+}
+
+func listParamsFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "handler", "api", "list_params.go")
+}
+
+// ensureListHelpers adds the Filters type shared by the services, and the
+// handler functions parsing the filter and sort params, to projects that
+// don't have them yet.
+func (s *Service) ensureListHelpers(input Input) error {
+	files := map[string]string{
+		listFiltersFilePath(input.WorkspaceFolder): "list_filters",
+		listParamsFilePath(input.WorkspaceFolder):  "list_params",
+	}
+
+	for path, templateName := range files {
+		if s.fileExists(path) {
+			continue
+		}
+
+		if err := s.ensureFileExists(path, templateName, input); err != nil {
+			return err
+		}
+
+		if err := s.runCommand(filepath.Dir(path), "goimports", "-w", filepath.Base(path)); err != nil {
+			return fmt.Errorf("failed running goimports: %w", err)
+		}
+	}
+
+	return nil
+}
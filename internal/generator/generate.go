@@ -29,12 +29,105 @@ func (*Service) CheckValidProject(_ context.Context, workspaceFolder string) err
 	return nil
 }
 
-//nolint:funlen,cyclop
+// Generate runs every generation step for the resource. If any step fails,
+// all files touched so far are restored and a *RollbackError is returned.
+// If parts of the resource are already in the project, nothing is written and
+// an *ExistingResourceError is returned, unless the service was created
+// WithForce.
 func (s *Service) Generate(ctx context.Context, input Input) error {
 	if err := ensureValidResourceName(input.Resource.String()); err != nil {
 		return err
 	}
 
+	s.beginRun()
+
+	if err := s.ensureNestable(input); err != nil {
+		return err
+	}
+
+	if err := input.ensureConstraintFields(input.columnNames()); err != nil {
+		return err
+	}
+
+	if err := input.ensureFullTextFields(); err != nil {
+		return err
+	}
+
+	if err := input.ensureBulkFields(); err != nil {
+		return err
+	}
+
+	existing, err := s.existingArtifacts(input)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) > 0 && !s.force {
+		return &ExistingResourceError{Resource: input.Resource.String(), Artifacts: existing}
+	}
+
+	if err = s.replace(ctx, input, len(existing) > 0); err != nil {
+		if s.dryRun {
+			return err
+		}
+
+		return s.rollback(input, err)
+	}
+
+	return nil
+}
+
+// replace clears out what is already in the project for the resource, if
+// anything, and then generates it. Both happen in one run, so a failure
+// restores the resource as it was.
+func (s *Service) replace(ctx context.Context, input Input, exists bool) error {
+	if exists {
+		if err := s.clearExisting(ctx, input); err != nil {
+			return fmt.Errorf("failed removing existing resource: %w", err)
+		}
+
+		// the manifest should only list what the generation itself produced
+		s.workspace.run = runRecord{}
+	}
+
+	return s.generate(ctx, input)
+}
+
+//nolint:funlen,cyclop
+func (s *Service) generate(ctx context.Context, input Input) error {
+	input, err := s.resolveJSONTypes(input)
+	if err != nil {
+		return err
+	}
+
+	if input, err = s.resolveKeys(s.resolveReferences(input)); err != nil {
+		return err
+	}
+
+	if input, err = s.resolveChildren(input); err != nil {
+		return err
+	}
+
+	if err = s.ensureTransactions(input); err != nil {
+		return fmt.Errorf("failed adding database provider: %w", err)
+	}
+
+	if err = s.ensureIDHelpers(input); err != nil {
+		return fmt.Errorf("failed adding id helpers: %w", err)
+	}
+
+	if err = s.ensureCursorHelpers(input); err != nil {
+		return fmt.Errorf("failed adding cursor helpers: %w", err)
+	}
+
+	if err = s.ensureListHelpers(input); err != nil {
+		return fmt.Errorf("failed adding list helpers: %w", err)
+	}
+
+	if err = s.ensureBulkHelpers(input); err != nil {
+		return fmt.Errorf("failed adding bulk helpers: %w", err)
+	}
+
 	// migration
 	if err := s.generateResourceMigration(ctx, input); err != nil {
 		return fmt.Errorf("failed generating resource migration: %w", err)
@@ -45,6 +138,12 @@ func (s *Service) Generate(ctx context.Context, input Input) error {
 		return fmt.Errorf("failed running make db-migrate: %w", err)
 	}
 
+	s.workspace.migrations++
+
+	if err := s.trackFile(filepath.Join(input.WorkspaceFolder, "internal", "database", "schema.sql")); err != nil {
+		return err
+	}
+
 	if err := s.runCommand(input.WorkspaceFolder, "make", "db-schema-dump"); err != nil {
 		return fmt.Errorf("failed running make db-schema-dump: %w", err)
 	}
@@ -54,7 +153,16 @@ func (s *Service) Generate(ctx context.Context, input Input) error {
 		return fmt.Errorf("failed generating sql methods: %w", err)
 	}
 
+	// point sqlc at the types of json fields and search vectors
+	if err := s.generateJSONTypes(ctx, input); err != nil {
+		return fmt.Errorf("failed generating json types: %w", err)
+	}
+
 	// run sqlc gen
+	if err := s.trackFolder(filepath.Join(input.WorkspaceFolder, "internal", "dbx")); err != nil {
+		return err
+	}
+
 	if err := s.runCommand(input.WorkspaceFolder, "make", "sqlc-gen"); err != nil {
 		return fmt.Errorf("failed running make sqlc-gen: %w", err)
 	}
@@ -108,6 +216,21 @@ func (s *Service) Generate(ctx context.Context, input Input) error {
 		return fmt.Errorf("failed generating frontend components: %w", err)
 	}
 
+	// record the resource in the manifest
+	if err := s.recordResource(input); err != nil {
+		return fmt.Errorf("failed updating manifest: %w", err)
+	}
+
+	// keep the template output to merge against on regeneration
+	if err := s.storePristineFiles(ctx, input); err != nil {
+		return fmt.Errorf("failed storing pristine files: %w", err)
+	}
+
+	// destroying the parent's rows now destroys the resource's too
+	if err := s.regenerateParent(ctx, input); err != nil {
+		return fmt.Errorf("failed regenerating parent: %w", err)
+	}
+
 	return nil
 }
 
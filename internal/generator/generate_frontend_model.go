@@ -6,40 +6,6 @@ import (
 	"path/filepath"
 )
 
-const frontendModelTemplate = `
-import dayjs from 'dayjs';
-import utc from 'dayjs/plugin/utc';
-
-dayjs.extend(utc);
-{{range $f := .Fields}}{{ if eq $f.Type "enum" }}
-{{ $f.EnumTypesFrontendModel }}
-{{end}}{{end}}
-
-export class {{  .Resource.CamelcaseSingular }} {
-  public id: string;
-
-  public createdAt: dayjs.Dayjs;
-
-  public updatedAt: dayjs.Dayjs;
-
-  {{range .Fields }}{{ .FrontendModelDeclaration }}
-  {{end}}
-
-  constructor(json: any) {
-    if (!json) {
-      return;
-    }
-
-    this.id = json.id;
-    this.createdAt = dayjs.utc(json.createdAt);
-    this.updatedAt = dayjs.utc(json.updatedAt);
-
-    {{range .Fields }}{{ .FrontendModelAssignment }}
-    {{end}}
-  }
-}
-`
-
 func (s *Service) generateFrontendModel(ctx context.Context, input Input) error {
 	folderPath := filepath.Join(input.WorkspaceFolder, "frontend", "src", "models")
 
@@ -53,10 +19,7 @@ func (s *Service) generateFrontendModel(ctx context.Context, input Input) error
 	if err := s.appendTemplateToFile(
 		ctx,
 		filePath,
-		0,
-		"",
-		"frontendModel",
-		frontendModelTemplate,
+		"frontend_model",
 		input,
 	); err != nil {
 		return fmt.Errorf("failed to generate frontend model: %w", err)
@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+var ErrDeclarationNotFound = errors.New("declaration not found")
+
+type goDeclKind string
+
+const (
+	goInterface goDeclKind = "interface"
+	goStruct    goDeclKind = "struct"
+	goFunction  goDeclKind = "func"
+)
+
+// injectIntoInterface adds the rendered methods to the end of the named
+// interface type.
+func (s *Service) injectIntoInterface(filePath string, name string, templateName string, input any) error {
+	return s.injectIntoGoDecl(filePath, goInterface, name, templateName, input)
+}
+
+// injectIntoStruct adds the rendered fields to the end of the named struct
+// type.
+func (s *Service) injectIntoStruct(filePath string, name string, templateName string, input any) error {
+	return s.injectIntoGoDecl(filePath, goStruct, name, templateName, input)
+}
+
+// injectIntoFunction adds the rendered statements to the end of the body of
+// the named top-level function.
+func (s *Service) injectIntoFunction(filePath string, name string, templateName string, input any) error {
+	return s.injectIntoGoDecl(filePath, goFunction, name, templateName, input)
+}
+
+// injectIntoLiteral adds the rendered elements to the end of the first
+// composite literal of the named type, e.g. dbx.CreatePostParams.
+func (s *Service) injectIntoLiteral(filePath string, typeName string, templateName string, input any) error {
+	return s.injectAtGoPos(filePath, "literal "+typeName, templateName, input, true, func(file *ast.File) token.Pos {
+		literal := findLiteral(file, typeName)
+		if literal == nil {
+			return token.NoPos
+		}
+
+		return literal.Rbrace
+	})
+}
+
+// injectBelowLiteral adds the rendered statements below the first statement
+// that assigns a composite literal of the named type, and the field
+// assignments that follow it, e.g. input.Name = request.Name.
+func (s *Service) injectBelowLiteral(filePath string, typeName string, templateName string, input any) error {
+	return s.injectAtGoPos(filePath, "assignment "+typeName, templateName, input, false, func(file *ast.File) token.Pos {
+		position := token.NoPos
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			block, isBlock := node.(*ast.BlockStmt)
+			if !isBlock || position.IsValid() {
+				return !position.IsValid()
+			}
+
+			for n, statement := range block.List {
+				variable := literalAssignment(statement, typeName)
+				if variable == "" {
+					continue
+				}
+
+				last := n
+				for last+1 < len(block.List) && assignsFieldOf(block.List[last+1], variable) {
+					last++
+				}
+
+				position = block.List[last].End()
+
+				break
+			}
+
+			return !position.IsValid()
+		})
+
+		return position
+	})
+}
+
+// injectAboveReturn adds the rendered statements above the return statement
+// that ends the named top-level function.
+func (s *Service) injectAboveReturn(filePath string, name string, templateName string, input any) error {
+	return s.injectAtGoPos(filePath, "return "+name, templateName, input, false, func(file *ast.File) token.Pos {
+		for _, decl := range file.Decls {
+			function, isFunction := decl.(*ast.FuncDecl)
+			if !isFunction || function.Recv != nil || function.Name.Name != name || function.Body == nil || len(function.Body.List) == 0 {
+				continue
+			}
+
+			if returnStmt, isReturn := function.Body.List[len(function.Body.List)-1].(*ast.ReturnStmt); isReturn {
+				return returnStmt.Pos()
+			}
+		}
+
+		return token.NoPos
+	})
+}
+
+// injectIntoGoDecl parses a Go file, and renders a template just inside the
+// closing brace of the named declaration, so that whatever follows the
+// declaration in the file is left alone. The result is gofmt'd.
+func (s *Service) injectIntoGoDecl(
+	filePath string,
+	kind goDeclKind,
+	name string,
+	templateName string,
+	input any,
+) error {
+	return s.injectAtGoPos(filePath, fmt.Sprintf("%s %s", kind, name), templateName, input, false, func(file *ast.File) token.Pos {
+		return closingBrace(file, kind, name)
+	})
+}
+
+// injectAtGoPos parses a Go file, and renders a template at the position the
+// find function returns for it. When the position closes a list of elements,
+// such as a composite literal, the last element on its line is given the comma
+// it needs before the rendered ones. The result is gofmt'd.
+func (s *Service) injectAtGoPos(
+	filePath string,
+	anchor string,
+	templateName string,
+	input any,
+	elements bool,
+	find func(file *ast.File) token.Pos,
+) error {
+	rendered, err := s.renderTemplate(templateName, input)
+	if err != nil {
+		return err
+	}
+
+	content, err := s.readFile(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open target file: %w", err)
+	}
+
+	fileSet := token.NewFileSet()
+
+	file, err := parser.ParseFile(fileSet, filePath, content, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	position := find(file)
+	if !position.IsValid() {
+		return fmt.Errorf("%s in %s: %w", anchor, filePath, ErrDeclarationNotFound)
+	}
+
+	offset := fileSet.Position(position).Offset
+
+	updated := append([]byte{}, content[:offset]...)
+
+	// start on a line of its own, without leaving a blank one
+	lineStart := bytes.LastIndexByte(content[:offset], '\n') + 1
+	if before := bytes.TrimSpace(content[lineStart:offset]); len(before) > 0 {
+		if elements && !bytes.HasSuffix(before, []byte("{")) && !bytes.HasSuffix(before, []byte(",")) {
+			updated = append(updated, ',')
+		}
+
+		updated = append(updated, '\n')
+	}
+
+	updated = append(updated, bytes.Trim(rendered, "\n")...)
+	updated = append(updated, '\n')
+	updated = append(updated, content[offset:]...)
+
+	formatted, err := format.Source(updated)
+	if err != nil {
+		return fmt.Errorf("failed to inject %s at %s: %w", templateName, anchor, err)
+	}
+
+	if err = s.writeFile(filePath, formatted); err != nil {
+		return fmt.Errorf("failed to write target file: %w", err)
+	}
+
+	s.recordAnchor(filePath, anchor, templateName)
+
+	return nil
+}
+
+// closingBrace finds the closing brace of the named declaration, or returns
+// token.NoPos if the file does not declare it.
+func closingBrace(file *ast.File, kind goDeclKind, name string) token.Pos {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if kind == goFunction && decl.Recv == nil && decl.Name.Name == name && decl.Body != nil {
+				return decl.Body.Rbrace
+			}
+		case *ast.GenDecl:
+			if decl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range decl.Specs {
+				typeSpec, isTypeSpec := spec.(*ast.TypeSpec)
+				if !isTypeSpec || typeSpec.Name.Name != name {
+					continue
+				}
+
+				switch declType := typeSpec.Type.(type) {
+				case *ast.InterfaceType:
+					if kind == goInterface {
+						return declType.Methods.Closing
+					}
+				case *ast.StructType:
+					if kind == goStruct {
+						return declType.Fields.Closing
+					}
+				}
+			}
+		}
+	}
+
+	return token.NoPos
+}
+
+// findLiteral finds the first composite literal of the named type.
+func findLiteral(file *ast.File, typeName string) *ast.CompositeLit {
+	var found *ast.CompositeLit
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if literal, isLiteral := node.(*ast.CompositeLit); isLiteral && found == nil && goTypeName(literal.Type) == typeName {
+			found = literal
+		}
+
+		return found == nil
+	})
+
+	return found
+}
+
+// goTypeName is the name of a type as written in the source, e.g.
+// dbx.CreatePostParams.
+func goTypeName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return goTypeName(expr.X) + "." + expr.Sel.Name
+	default:
+		return ""
+	}
+}
+
+// literalAssignment is the variable a statement assigns a composite literal of
+// the named type to, if it does.
+func literalAssignment(statement ast.Stmt, typeName string) string {
+	assignment, isAssignment := statement.(*ast.AssignStmt)
+	if !isAssignment || len(assignment.Lhs) != 1 || len(assignment.Rhs) != 1 {
+		return ""
+	}
+
+	literal, isLiteral := assignment.Rhs[0].(*ast.CompositeLit)
+	if !isLiteral || goTypeName(literal.Type) != typeName {
+		return ""
+	}
+
+	return goTypeName(assignment.Lhs[0])
+}
+
+// assignsFieldOf is true if a statement assigns to a field of the variable.
+func assignsFieldOf(statement ast.Stmt, variable string) bool {
+	assignment, isAssignment := statement.(*ast.AssignStmt)
+	if !isAssignment || len(assignment.Lhs) != 1 {
+		return false
+	}
+
+	field, isField := assignment.Lhs[0].(*ast.SelectorExpr)
+
+	return isField && goTypeName(field.X) == variable
+}
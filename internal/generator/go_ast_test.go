@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type goInjectionFunc func(s *Service, filePath string, name string, templateName string, input any) error
+
+//nolint:funlen,maintidx
+func TestGoInjections(t *testing.T) {
+	tests := []struct {
+		name     string
+		inject   goInjectionFunc
+		target   string
+		source   string
+		rendered string
+		expected string
+		err      error
+	}{
+		{
+			name:     "struct with a trailing comment",
+			inject:   (*Service).injectIntoStruct,
+			target:   "Post",
+			source:   "type Post struct {\n\tTitle string // the title\n}\n\nfunc other() {}\n",
+			rendered: "Body string",
+			expected: "type Post struct {\n\tTitle string // the title\n\tBody  string\n}\n\nfunc other() {}\n",
+		},
+		{
+			name:     "empty struct",
+			inject:   (*Service).injectIntoStruct,
+			target:   "Params",
+			source:   "type Params struct{}\n",
+			rendered: "Title string",
+			expected: "type Params struct {\n\tTitle string\n}\n",
+		},
+		{
+			name:     "empty interface",
+			inject:   (*Service).injectIntoInterface,
+			target:   "Provider",
+			source:   "type Provider interface{}\n",
+			rendered: "Close() error",
+			expected: "type Provider interface {\n\tClose() error\n}\n",
+		},
+		{
+			name:     "second type of a group",
+			inject:   (*Service).injectIntoStruct,
+			target:   "Second",
+			source:   "type (\n\tFirst struct {\n\t\tA int\n\t}\n\tSecond struct {\n\t\tB int\n\t}\n)\n",
+			rendered: "C int",
+			expected: "type (\n\tFirst struct {\n\t\tA int\n\t}\n\tSecond struct {\n\t\tB int\n\t\tC int\n\t}\n)\n",
+		},
+		{
+			name:     "struct named like an interface",
+			inject:   (*Service).injectIntoInterface,
+			target:   "Post",
+			source:   "type Post struct{}\n",
+			rendered: "Close() error",
+			err:      ErrDeclarationNotFound,
+		},
+		{
+			name:     "missing declaration",
+			inject:   (*Service).injectIntoStruct,
+			target:   "Comment",
+			source:   "type Post struct{}\n",
+			rendered: "Title string",
+			err:      ErrDeclarationNotFound,
+		},
+		{
+			name:     "end of a function",
+			inject:   (*Service).injectIntoFunction,
+			target:   "register",
+			source:   "func (s *Service) register() {}\n\nfunc register() {\n\tfirst()\n\t// trailing\n}\n",
+			rendered: "second()",
+			expected: "func (s *Service) register() {}\n\nfunc register() {\n\tfirst()\n\t// trailing\n\tsecond()\n}\n",
+		},
+		{
+			name:     "multi-line literal",
+			inject:   (*Service).injectIntoLiteral,
+			target:   "dbx.CreatePostParams",
+			source:   "func create() {\n\tuse(dbx.CreatePostParams{\n\t\tTitle: title,\n\t})\n}\n",
+			rendered: "Body: body,",
+			expected: "func create() {\n\tuse(dbx.CreatePostParams{\n\t\tTitle: title,\n\t\tBody:  body,\n\t})\n}\n",
+		},
+		{
+			name:     "one-line literal",
+			inject:   (*Service).injectIntoLiteral,
+			target:   "dbx.CreatePostParams",
+			source:   "func create() {\n\tuse(dbx.CreatePostParams{Title: title})\n}\n",
+			rendered: "Body: body,",
+			expected: "func create() {\n\tuse(dbx.CreatePostParams{Title: title,\n\t\tBody: body,\n\t})\n}\n",
+		},
+		{
+			name:     "empty literal",
+			inject:   (*Service).injectIntoLiteral,
+			target:   "dbx.CreatePostParams",
+			source:   "func create() {\n\tuse(dbx.CreatePostParams{})\n}\n",
+			rendered: "Body: body,",
+			expected: "func create() {\n\tuse(dbx.CreatePostParams{\n\t\tBody: body,\n\t})\n}\n",
+		},
+		{
+			name:     "literal of another type",
+			inject:   (*Service).injectIntoLiteral,
+			target:   "dbx.CreatePostParams",
+			source:   "func create() {\n\tuse(CreatePostParams{})\n}\n",
+			rendered: "Body: body,",
+			err:      ErrDeclarationNotFound,
+		},
+		{
+			name:     "below a literal and its field assignments",
+			inject:   (*Service).injectBelowLiteral,
+			target:   "blog.CreatePostParams",
+			source:   "func create() {\n\tinput := blog.CreatePostParams{}\n\tinput.Title = request.Title\n\n\tsave(input)\n}\n",
+			rendered: "input.Body = request.Body",
+			expected: "func create() {\n\tinput := blog.CreatePostParams{}\n\tinput.Title = request.Title\n\tinput.Body = request.Body\n\n\tsave(input)\n}\n",
+		},
+		{
+			name:     "above the final return",
+			inject:   (*Service).injectAboveReturn,
+			target:   "PostFromModel",
+			source:   "func PostFromModel() Post {\n\tif ok {\n\t\treturn Post{}\n\t}\n\n\treturn item\n}\n",
+			rendered: "item.Body = m.Body\n",
+			expected: "func PostFromModel() Post {\n\tif ok {\n\t\treturn Post{}\n\t}\n\n\titem.Body = m.Body\n\treturn item\n}\n",
+		},
+		{
+			name:     "function not ending in a return",
+			inject:   (*Service).injectAboveReturn,
+			target:   "PostFromModel",
+			source:   "func PostFromModel() {\n\tif ok {\n\t\treturn\n\t}\n}\n",
+			rendered: "item.Body = m.Body",
+			err:      ErrDeclarationNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			templatesFolder := t.TempDir()
+			if err := os.WriteFile(filepath.Join(templatesFolder, "injected.go.tmpl"), []byte(test.rendered), 0o600); err != nil {
+				t.Fatalf("failed to write template: %v", err)
+			}
+
+			filePath := filepath.Join(t.TempDir(), "file.go")
+			if err := os.WriteFile(filePath, []byte("package blog\n\n"+test.source), 0o600); err != nil {
+				t.Fatalf("failed to write source: %v", err)
+			}
+
+			err := test.inject(New(WithTemplatesFolder(templatesFolder)), filePath, test.target, "injected", nil)
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to inject: %v", err)
+			}
+
+			if content := readTestFile(t, filePath); content != "package blog\n\n"+test.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", test.expected, strings.TrimPrefix(content, "package blog\n\n"))
+			}
+		})
+	}
+}
+
+func TestLiteralAssignment(t *testing.T) {
+	tests := []struct {
+		statement string
+		expected  string
+	}{
+		{"input := blog.CreatePostParams{}", "input"},
+		{"input = blog.CreatePostParams{Title: title}", "input"},
+		{"input := &blog.CreatePostParams{}", ""},
+		{"input := blog.UpdatePostParams{}", ""},
+		{"a, b := blog.CreatePostParams{}, 1", ""},
+		{"save(blog.CreatePostParams{})", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.statement, func(t *testing.T) {
+			file, err := parser.ParseFile(token.NewFileSet(), "file.go", "package blog\n\nfunc f() {\n\t"+test.statement+"\n}\n", 0)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			statement := file.Decls[0].(*ast.FuncDecl).Body.List[0] //nolint:forcetypeassert
+
+			if variable := literalAssignment(statement, "blog.CreatePostParams"); variable != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, variable)
+			}
+		})
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
-	"text/template"
+	"time"
+
+	"github.com/samber/lo"
 )
 
 var (
@@ -19,48 +21,49 @@ var (
 	ErrAnchorNotFound = errors.New("anchor not found")
 )
 
-func (*Service) ensureFolderExists(path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		err := os.MkdirAll(path, 0o755) //nolint:gosec,gomnd
-		if err != nil {
+func (s *Service) ensureFolderExists(path string) error {
+	if !s.folderExists(path) {
+		if err := s.makeFolder(path); err != nil {
 			return fmt.Errorf("failed to create migrations path: %w", err)
 		}
 	}
 
-	if info, err := os.Stat(path); os.IsNotExist(err) || !info.IsDir() {
+	if !s.folderExists(path) {
 		return fmt.Errorf("migrations path not found: %w", ErrInvalidPath)
 	}
 
 	return nil
 }
 
-func (*Service) ensureFileExists(path string, templateName string, templateString string, templateInput any) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		file, err := os.Create(path) //nolint:gosec
-		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
-		}
-
-		defer file.Close() //nolint:errcheck
-
-		tmpl, err := template.New(templateName).Parse(templateString)
+func (s *Service) ensureFileExists(path string, templateName string, templateInput any) error {
+	if !s.fileExists(path) {
+		content, err := s.renderTemplate(templateName, templateInput)
 		if err != nil {
-			return fmt.Errorf("failed to parse template: %w", err)
+			return err
 		}
 
-		if err = tmpl.Execute(file, templateInput); err != nil {
-			return fmt.Errorf("failed to execute template: %w", err)
+		if err = s.writeFile(path, content); err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
 		}
 	}
 
-	if _, err := os.Stat(path); os.IsNotExist(err) {
+	if !s.fileExists(path) {
 		return fmt.Errorf("path not found: %w", ErrInvalidPath)
 	}
 
 	return nil
 }
 
-func (*Service) runCommand(workspaceFolder string, command string, args ...string) error {
+func (s *Service) runCommand(workspaceFolder string, command string, args ...string) error {
+	if s.dryRun {
+		s.workspace.commands = append(
+			s.workspace.commands,
+			fmt.Sprintf("(cd %s && %s %s)", workspaceFolder, command, strings.Join(args, " ")),
+		)
+
+		return nil
+	}
+
 	cmd := exec.Command(command, args...)
 	cmd.Dir = workspaceFolder
 	cmd.Stdout = os.Stdout
@@ -73,121 +76,286 @@ func (*Service) runCommand(workspaceFolder string, command string, args ...strin
 	return nil
 }
 
-func (*Service) appendTemplateToFile(
+func (s *Service) appendTemplateToFile(
 	_ context.Context,
 	filePath string,
-	reverseOffset int,
-	suffix string,
 	templateName string,
-	templateString string,
 	input any,
 ) error {
-	tmpl, err := template.New(templateName).Parse(templateString)
+	rendered, err := s.renderTemplate(templateName, input)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return err
 	}
 
-	var queriesFile *os.File
-
-	if reverseOffset != 0 {
-		queriesFile, err = os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, 0o644) //nolint:gomnd,gosec
-		if err != nil {
-			return fmt.Errorf("failed to open queries file: %w", err)
-		}
-
-		defer queriesFile.Close() //nolint:errcheck
+	content := []byte{}
 
-		if _, err = queriesFile.Seek(int64(-reverseOffset), io.SeekEnd); err != nil {
-			return fmt.Errorf("failed to seek to the end of the file: %w", err)
-		}
-	} else {
-		queriesFile, err = os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) //nolint:gomnd,gosec
+	if s.fileExists(filePath) {
+		content, err = s.readFile(filePath)
 		if err != nil {
 			return fmt.Errorf("failed to open queries file: %w", err)
 		}
 	}
 
-	if err = tmpl.Execute(queriesFile, input); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
-	}
+	content = append(content, rendered...)
 
-	if suffix != "" {
-		if _, err := io.WriteString(queriesFile, suffix); err != nil {
-			return fmt.Errorf("failed to write suffix: %w", err)
-		}
+	if err = s.writeFile(filePath, content); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
 	}
 
+	s.recordAnchor(filePath, endOfFileAnchor, templateName)
+
 	return nil
 }
 
-func (*Service) injectTemplateAboveLine(
+func (s *Service) injectTemplateAboveLine(
+	filePath string,
+	anchorLine string,
+	templateName string,
+	input any,
+) error {
+	return s.injectTemplateAtLine(filePath, anchorLine, 0, templateName, input)
+}
+
+func (s *Service) injectTemplateBelowLine(
 	filePath string,
 	anchorLine string,
 	templateName string,
-	templateString string,
 	input any,
 ) error {
-	tmpl, err := template.New(templateName).Parse(templateString)
+	return s.injectTemplateAtLine(filePath, anchorLine, 1, templateName, input)
+}
+
+// injectTemplateAtLine renders a template into a file at the last line matching
+// the anchor, shifted by offset lines (0 places it above the anchor, 1 below).
+func (s *Service) injectTemplateAtLine(
+	filePath string,
+	anchorLine string,
+	offset int,
+	templateName string,
+	input any,
+) error {
+	rendered, err := s.renderTemplate(templateName, input)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return err
 	}
 
-	targetFile, err := os.Open(filePath)
+	targetLines, err := s.readLines(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open target file: %w", err)
 	}
 
-	defer targetFile.Close() //nolint:errcheck
-
-	targetLines := []string{}
-	scanner := bufio.NewScanner(targetFile)
-
 	anchorIndex := -1
-	lineNumber := 0
-	for scanner.Scan() {
-		line := scanner.Text()
-		targetLines = append(targetLines, line)
 
-		if strings.TrimSpace(line) == anchorLine {
+	// compare with whitespace collapsed, since gofmt realigns struct fields
+	for lineNumber, line := range targetLines {
+		if strings.Join(strings.Fields(line), " ") == strings.Join(strings.Fields(anchorLine), " ") {
 			anchorIndex = lineNumber
 		}
+	}
+
+	if anchorIndex == -1 {
+		return fmt.Errorf("%s in %s: %w", anchorLine, filePath, ErrAnchorNotFound)
+	}
+
+	insertIndex := anchorIndex + offset
+
+	finalLines := append([]string{}, targetLines[:insertIndex]...)
+	finalLines = append(finalLines, string(rendered))
+	finalLines = append(finalLines, targetLines[insertIndex:]...)
+
+	err = s.writeLinesToFile(finalLines, filePath)
+	if err != nil {
+		return fmt.Errorf("failed to write target file: %w", err)
+	}
+
+	s.recordAnchor(filePath, anchorLine, templateName)
+
+	return nil
+}
+
+// recordAnchor notes where a template was placed in a file that existed
+// before the run. Files the run created are recorded whole.
+func (s *Service) recordAnchor(filePath string, anchorLine string, templateName string) {
+	if s.workspace.run.isCreated(filepath.Clean(filePath)) {
+		return
+	}
+
+	s.workspace.run.anchors = append(s.workspace.run.anchors, ManifestAnchor{
+		File:     filepath.Clean(filePath),
+		Anchor:   strings.TrimSpace(anchorLine),
+		Template: templateName,
+	})
+}
+
+func (s *Service) writeLinesToFile(lines []string, path string) error {
+	buf := &bytes.Buffer{}
+
+	for _, line := range lines {
+		fmt.Fprintln(buf, line)
+	}
+
+	return s.writeFile(path, buf.Bytes())
+}
 
-		lineNumber += 1
+func (s *Service) readLines(filePath string) ([]string, error) {
+	content, err := s.readFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
 	}
+
 	if err = scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read target file: %w", err)
+		return nil, fmt.Errorf("failed to read %s: %w", filePath, err)
 	}
 
-	if anchorIndex == -1 {
-		return ErrAnchorNotFound
+	return lines, nil
+}
+
+// removeLinesFromFile drops every line for which the matcher returns true.
+func (s *Service) removeLinesFromFile(filePath string, matcher func(line string) bool) error {
+	lines, err := s.readLines(filePath)
+	if err != nil {
+		return err
+	}
+
+	finalLines := lo.Reject(lines, func(line string, _ int) bool { return matcher(line) })
+
+	if len(finalLines) == len(lines) {
+		return nil
+	}
+
+	// the blank line before a block removed from the end goes with it
+	for len(finalLines) > 0 && strings.TrimSpace(finalLines[len(finalLines)-1]) == "" {
+		finalLines = finalLines[:len(finalLines)-1]
 	}
 
-	templateBuf := &bytes.Buffer{}
-	if err = tmpl.Execute(templateBuf, input); err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	return s.writeLinesToFile(finalLines, filePath)
+}
+
+// removeInjectedTemplate is the inverse of injectTemplateAboveLine. It renders
+// the same template and removes the block it produced, along with the blank
+// line that injection leaves behind.
+func (s *Service) removeInjectedTemplate(
+	filePath string,
+	templateName string,
+	input any,
+) error {
+	rendered, err := s.renderTemplate(templateName, input)
+	if err != nil {
+		return err
 	}
 
-	finalLines := append(targetLines[:anchorIndex+1], targetLines[anchorIndex:]...)
-	finalLines[anchorIndex] = templateBuf.String()
+	block := lo.Map(
+		strings.Split(strings.TrimRight(string(rendered), "\n"), "\n"),
+		func(line string, _ int) string { return strings.TrimSpace(line) },
+	)
 
-	err = writeLinesToFile(finalLines, filePath)
+	lines, err := s.readLines(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to write target file: %w", err)
+		return err
+	}
+
+	for start := 0; start+len(block) <= len(lines); start++ {
+		matched := true
+
+		for i, blockLine := range block {
+			if strings.TrimSpace(lines[start+i]) != blockLine {
+				matched = false
+
+				break
+			}
+		}
+
+		if !matched {
+			continue
+		}
+
+		end := start + len(block)
+		if end < len(lines) && strings.TrimSpace(lines[end]) == "" {
+			end++
+		}
+
+		return s.writeLinesToFile(append(lines[:start], lines[end:]...), filePath)
 	}
 
 	return nil
 }
 
-func writeLinesToFile(lines []string, path string) error {
-	file, err := os.Create(path)
+// removeSQLQueries removes the named sqlc query blocks from a queries file.
+// A block starts at its "-- name:" comment and runs until the next one.
+func (s *Service) removeSQLQueries(filePath string, names []string) error {
+	lines, err := s.readLines(filePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	w := bufio.NewWriter(file)
+	finalLines := []string{}
+	removing := false
+
 	for _, line := range lines {
-		fmt.Fprintln(w, line)
+		if name, found := sqlQueryName(line); found {
+			removing = lo.Contains(names, name)
+		}
+
+		if !removing {
+			finalLines = append(finalLines, line)
+		}
 	}
-	return w.Flush()
+
+	if len(finalLines) == len(lines) {
+		return nil
+	}
+
+	// the blank line before a block removed from the end goes with it
+	for len(finalLines) > 0 && strings.TrimSpace(finalLines[len(finalLines)-1]) == "" {
+		finalLines = finalLines[:len(finalLines)-1]
+	}
+
+	return s.writeLinesToFile(finalLines, filePath)
+}
+
+func sqlQueryName(line string) (string, bool) {
+	words := strings.Fields(line)
+
+	//nolint:gomnd
+	if len(words) < 3 || words[0] != "--" || words[1] != "name:" {
+		return "", false
+	}
+
+	return words[2], true
+}
+
+// nextMigrationVersion returns the current timestamp, moved past the newest
+// migration already in the folder so that migrations written in quick
+// succession do not share a version.
+func (s *Service) nextMigrationVersion(migrationsFolder string) (string, error) {
+	const layout = "20060102150405"
+
+	paths, err := filepath.Glob(filepath.Join(migrationsFolder, "*.sql"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	for path := range s.workspace.overlay {
+		if filepath.Dir(path) == filepath.Clean(migrationsFolder) {
+			paths = append(paths, path)
+		}
+	}
+
+	version := time.Now().Truncate(time.Second)
+
+	for _, path := range paths {
+		existing, parseErr := time.ParseInLocation(layout, strings.SplitN(filepath.Base(path), "_", 2)[0], time.Local) //nolint:gomnd
+		if parseErr == nil && !existing.Before(version) {
+			version = existing.Add(time.Second)
+		}
+	}
+
+	return version.Format(layout), nil
 }
@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// testMakefile stands in for the project's database and sqlc targets. Each
+// target notes that it ran in commands.log.
+const testMakefile = `db-migrate:
+	echo migrate >> commands.log
+db-rollback:
+	echo rollback >> commands.log
+db-schema-dump:
+	echo dump >> commands.log
+sqlc-gen:
+	echo sqlc >> commands.log
+	mkdir -p internal/dbx && touch internal/dbx/x.go
+`
+
+// newTestWorkspace copies the sample webapp into a temporary folder with the
+// given Makefile, and puts a goimports on the PATH that only gofmts, since
+// the project's packages can't be resolved.
+func newTestWorkspace(t *testing.T, makefile string) string {
+	t.Helper()
+
+	workspaceFolder := t.TempDir()
+
+	err := filepath.WalkDir(filepath.Join("..", "..", "webapp"), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(filepath.Join("..", "..", "webapp"), path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(workspaceFolder, relativePath)
+
+		if entry.IsDir() {
+			return os.MkdirAll(target, 0o755) //nolint:gomnd
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, content, 0o600) //nolint:gomnd
+	})
+	if err != nil {
+		t.Fatalf("failed to copy webapp: %v", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(workspaceFolder, "Makefile"), []byte(makefile), 0o600); err != nil { //nolint:gomnd
+		t.Fatalf("failed to write Makefile: %v", err)
+	}
+
+	gofmt, err := exec.LookPath("gofmt")
+	if err != nil {
+		t.Fatalf("failed to find gofmt: %v", err)
+	}
+
+	binFolder := t.TempDir()
+
+	if err = os.WriteFile(filepath.Join(binFolder, "goimports"), []byte("#!/bin/sh\nexec "+gofmt+" \"$@\"\n"), 0o755); err != nil { //nolint:gomnd,gosec
+		t.Fatalf("failed to write goimports: %v", err)
+	}
+
+	t.Setenv("PATH", binFolder+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	return workspaceFolder
+}
+
+// testInput is a resource with the given fields, as the resource command
+// would parse it.
+func testInput(t *testing.T, workspaceFolder string, resource string, fieldStrings ...string) Input {
+	t.Helper()
+
+	fields := []InputField{}
+
+	for _, fieldString := range fieldStrings {
+		field, err := ParseField("blog", resource, fieldString)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", fieldString, err)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return Input{
+		WorkspaceFolder: workspaceFolder,
+		Service:         TemplateName("blog"),
+		Resource:        TemplateName(resource),
+		Fields:          fields,
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	return string(content)
+}
+
+func manifestEntry(t *testing.T, workspaceFolder string, resource string) ManifestResource {
+	t.Helper()
+
+	manifest, err := New().LoadManifest(workspaceFolder)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+
+	entry, found := manifest.Find(resource)
+	if !found {
+		t.Fatalf("expected %s in the manifest", resource)
+	}
+
+	return entry
+}
@@ -0,0 +1,328 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/samber/lo"
+)
+
+var ErrInvalidIDStrategy = errors.New("invalid id strategy")
+
+// IDStrategy is how the primary keys of a resource's table are typed and
+// generated. Resources generated before it was configurable, and those that
+// don't pick one, have uuid v4 keys.
+type IDStrategy string
+
+const (
+	IDStrategyUUID      IDStrategy = "uuid"
+	IDStrategyUUIDv7    IDStrategy = "uuidv7"
+	IDStrategyBigserial IDStrategy = "bigserial"
+	IDStrategyULID      IDStrategy = "ulid"
+)
+
+//nolint:gochecknoglobals
+var idStrategies = []IDStrategy{
+	IDStrategyUUID,
+	IDStrategyUUIDv7,
+	IDStrategyBigserial,
+	IDStrategyULID,
+}
+
+func ParseIDStrategy(value string) (IDStrategy, error) {
+	if value == "" {
+		return IDStrategyUUID, nil
+	}
+
+	if !lo.Contains(idStrategies, IDStrategy(value)) {
+		return "", fmt.Errorf("%q: %w", value, ErrInvalidIDStrategy)
+	}
+
+	return IDStrategy(value), nil
+}
+
+func (s IDStrategy) orDefault() IDStrategy {
+	if s == "" {
+		return IDStrategyUUID
+	}
+
+	return s
+}
+
+// PrimaryKeySQL is the id column of a new table. uuid v4 and bigserial keys
+// are generated by Postgres, uuid v7 and ULID keys by the service.
+func (s IDStrategy) PrimaryKeySQL() string {
+	switch s.orDefault() {
+	case IDStrategyBigserial:
+		return "id bigserial PRIMARY KEY"
+	case IDStrategyUUIDv7, IDStrategyULID:
+		return "id " + s.SQLType() + " PRIMARY KEY"
+	default:
+		return "id uuid PRIMARY KEY DEFAULT gen_random_uuid()"
+	}
+}
+
+// SQLType is the type of the id column, and of the columns referencing it.
+func (s IDStrategy) SQLType() string {
+	switch s.orDefault() {
+	case IDStrategyBigserial:
+		return "bigint"
+	case IDStrategyULID:
+		return "text"
+	default:
+		return "uuid"
+	}
+}
+
+func (s IDStrategy) GoType() string {
+	switch s.orDefault() {
+	case IDStrategyBigserial:
+		return "int64"
+	case IDStrategyULID:
+		return "string"
+	default:
+		return "uuid.UUID"
+	}
+}
+
+func (s IDStrategy) TypescriptType() string {
+	if s.orDefault() == IDStrategyBigserial {
+		return "number"
+	}
+
+	return "string"
+}
+
+// TypescriptParam is the TypeScript expression for a key read from the route
+// params, which are strings or undefined.
+func (s IDStrategy) TypescriptParam(value string) string {
+	if s.orDefault() == IDStrategyBigserial {
+		return "Number(" + value + ")"
+	}
+
+	return value + " || ''"
+}
+
+// AppGenerated is true for keys that the create query is given, rather than
+// defaulted by Postgres.
+func (s IDStrategy) AppGenerated() bool {
+	return s.orDefault() == IDStrategyUUIDv7 || s.orDefault() == IDStrategyULID
+}
+
+// NewIDGo is the Go expression for a new key of an AppGenerated strategy.
+// uuid v4 keys can be made by the service too, as bulk creates do.
+func (s IDStrategy) NewIDGo() string {
+	switch s.orDefault() {
+	case IDStrategyULID:
+		return "ulid.Make().String()"
+	case IDStrategyUUID:
+		return "uuid.New()"
+	default:
+		return "uuid.Must(uuid.NewV7())"
+	}
+}
+
+// ParseFunc is the function handlers parse the key out of a path param with.
+// parseInt64ID and parseULID are in the project's handler/api/ids.go.
+func (s IDStrategy) ParseFunc() string {
+	switch s.orDefault() {
+	case IDStrategyBigserial:
+		return "parseInt64ID"
+	case IDStrategyULID:
+		return "parseULID"
+	default:
+		return "uuid.Parse"
+	}
+}
+
+// PresentGo is the Go expression presenting a key of the strategy in JSON.
+func (s IDStrategy) PresentGo(value string) string {
+	if s.orDefault() == IDStrategyBigserial || s.orDefault() == IDStrategyULID {
+		return value
+	}
+
+	return value + ".String()"
+}
+
+// FormatGo is the Go expression for a key of the strategy as a string, as
+// used in storage paths.
+func (s IDStrategy) FormatGo(value string) string {
+	switch s.orDefault() {
+	case IDStrategyBigserial:
+		return "strconv.FormatInt(" + value + ", 10)"
+	case IDStrategyULID:
+		return value
+	default:
+		return value + ".String()"
+	}
+}
+
+// PresenterGoType is the type a key is presented as.
+func (s IDStrategy) PresenterGoType() string {
+	if s.orDefault() == IDStrategyBigserial {
+		return "int64"
+	}
+
+	return "string"
+}
+
+// memberWrapper is the call that wraps a handler of a single record, up to
+// its handler func. uuid keys are parsed by the project's own wrappers, and
+// the others by the generic ones in handler/api/ids.go.
+func memberWrapper(id IDStrategy) string {
+	if id.orDefault() == IDStrategyUUID {
+		return "wrapWithAuthForMember("
+	}
+
+	return "wrapWithAuthForMemberID(" + id.ParseFunc() + ", "
+}
+
+func childWrapper(parentID IDStrategy) string {
+	if parentID.orDefault() == IDStrategyUUID {
+		return "wrapWithAuthForChild("
+	}
+
+	return "wrapWithAuthForChildID(" + parentID.ParseFunc() + ", "
+}
+
+func childMemberWrapper(parentID IDStrategy, id IDStrategy) string {
+	if parentID.orDefault() == IDStrategyUUID && id.orDefault() == IDStrategyUUID {
+		return "wrapWithAuthForChildMember("
+	}
+
+	return "wrapWithAuthForChildMemberID(" + parentID.ParseFunc() + ", " + id.ParseFunc() + ", "
+}
+
+// parentKey is the key strategy of a resource's parent, which its parent
+// field references.
+func parentKey(parent *TemplateName, fields []ManifestField) IDStrategy {
+	if parent == nil {
+		return IDStrategyUUID
+	}
+
+	for _, field := range fields {
+		if field.Type == FieldTypeReferences && field.Name == parent.UnderscoreSingular()+"_id" {
+			return field.TableID.orDefault()
+		}
+	}
+
+	return IDStrategyUUID
+}
+
+func (i Input) MemberWrapper() string {
+	return memberWrapper(i.ID)
+}
+
+func (i Input) ChildWrapper() string {
+	return childWrapper(i.ParentID)
+}
+
+func (i Input) ChildMemberWrapper() string {
+	return childMemberWrapper(i.ParentID, i.ID)
+}
+
+// UsesIDHelpers is true for resources whose handlers use the generic wrappers.
+func (i Input) UsesIDHelpers() bool {
+	return i.ID.orDefault() != IDStrategyUUID || i.ParentID != IDStrategyUUID
+}
+
+func (f InputField) MemberWrapper() string {
+	return memberWrapper(f.ResourceID)
+}
+
+func (f InputField) ChildMemberWrapper() string {
+	return childMemberWrapper(f.ParentID, f.ResourceID)
+}
+
+// HasManyWrapper wraps the handler listing the records that reference one of
+// the table the field points at.
+func (f InputField) HasManyWrapper() string {
+	return memberWrapper(f.TableID)
+}
+
+// keyField is a references field with a non-uuid target, seen as the plain
+// field of the same type as the target's key.
+func (f InputField) keyField() (InputField, bool) {
+	if f.Type != FieldTypeReferences {
+		return f, false
+	}
+
+	switch f.TableID.orDefault() {
+	case IDStrategyBigserial:
+		f.Type = FieldTypeBigint
+	case IDStrategyULID:
+		f.Type = FieldTypeString
+	default:
+		return f, false
+	}
+
+	return f, true
+}
+
+// resolveKeys records the key strategy of the table each references field
+// points at, and whether the resource's parent is soft deleted. Tables that
+// oxgen didn't generate have uuid keys.
+func (s *Service) resolveKeys(input Input) (Input, error) {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return Input{}, err
+	}
+
+	fields := append([]InputField{}, input.Fields...)
+
+	for i, field := range fields {
+		if field.Type != FieldTypeReferences || field.TableID != "" {
+			continue
+		}
+
+		fields[i].TableID = IDStrategyUUID
+
+		if field.Table == input.Resource.UnderscorePlural() {
+			fields[i].TableID = input.ID.orDefault()
+
+			continue
+		}
+
+		for _, entry := range manifest.Resources {
+			if TemplateName(entry.Resource).UnderscorePlural() == field.Table {
+				fields[i].TableID = entry.ID.orDefault()
+			}
+		}
+	}
+
+	input.Fields = fields
+
+	// fields added to an existing resource come without its parent field
+	if input.ParentID == "" {
+		input.ParentID = parentKey(input.Parent, manifestFields(fields))
+	}
+
+	input.ParentSoftDelete = input.Parent != nil && manifest.softDeleted(*input.Parent)
+
+	return input.withResourceOptions(), nil
+}
+
+func idHelpersFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "handler", "api", "ids.go")
+}
+
+// ensureIDHelpers adds the generic handler wrappers to the project the first
+// time a resource with non-uuid keys is generated.
+func (s *Service) ensureIDHelpers(input Input) error {
+	path := idHelpersFilePath(input.WorkspaceFolder)
+
+	if !input.UsesIDHelpers() || s.fileExists(path) {
+		return nil
+	}
+
+	if err := s.ensureFileExists(path, "id_helpers", input); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(filepath.Dir(path), "goimports", "-w", filepath.Base(path)); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
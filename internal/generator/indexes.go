@@ -0,0 +1,178 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var ErrUnknownConstraintField = errors.New("constraint refers to an unknown field")
+
+// TableIndex is an index created along with the columns it covers, and
+// dropped by the matching down migration.
+type TableIndex struct {
+	Name    string
+	Table   string
+	Method  string
+	Columns string
+	Where   string
+}
+
+func (x TableIndex) CreateSQL() string {
+	sql := "CREATE INDEX " + x.Name + " ON " + x.Table
+
+	if x.Method != "" {
+		sql += " USING " + x.Method
+	}
+
+	sql += " (" + x.Columns + ")"
+
+	if x.Where != "" {
+		sql += " WHERE " + x.Where
+	}
+
+	return sql + ";"
+}
+
+func (x TableIndex) DropSQL() string {
+	return "DROP INDEX " + x.Name + ";"
+}
+
+// TableConstraint is a unique constraint over several columns of a table.
+type TableConstraint struct {
+	Name    string
+	Columns []string
+}
+
+func (c TableConstraint) SQL() string {
+	return "CONSTRAINT " + c.Name + " UNIQUE (" + strings.Join(c.Columns, ", ") + ")"
+}
+
+// Indexes are the indexes of a new resource's table: one for each of its
+// fields that needs one, a trigram index backing the ILIKE search on the
+// query field or a GIN index on the search vector of full-text search, the
+// partial indexes for listing live and deleted rows of soft
+// deleted resources, and the (updated_at, id) index that cursor pages are
+// read from.
+func (i Input) Indexes() []TableIndex {
+	table := i.Resource.UnderscorePlural()
+	indexes := i.FieldIndexes()
+
+	recentColumns := "updated_at DESC"
+	if i.CursorPagination() {
+		recentColumns = "updated_at DESC, id DESC"
+	}
+
+	if i.FullTextSearch() {
+		indexes = append(indexes, TableIndex{
+			Name:    table + "_" + searchVectorColumn + "_idx",
+			Table:   table,
+			Method:  "gin",
+			Columns: searchVectorColumn,
+		})
+	} else if i.HasSearch {
+		indexes = append(indexes, TableIndex{
+			Name:    table + "_" + i.SearchField + "_trgm_idx",
+			Table:   table,
+			Method:  "gin",
+			Columns: i.SearchField + " gin_trgm_ops",
+		})
+	}
+
+	if i.SoftDelete {
+		indexes = append(indexes,
+			TableIndex{
+				Name:    table + "_live_updated_at_idx",
+				Table:   table,
+				Columns: recentColumns,
+				Where:   "deleted_at IS NULL",
+			},
+			TableIndex{
+				Name:    table + "_deleted_at_idx",
+				Table:   table,
+				Columns: "deleted_at DESC",
+				Where:   "deleted_at IS NOT NULL",
+			},
+		)
+	} else if i.CursorPagination() {
+		indexes = append(indexes, TableIndex{
+			Name:    table + "_updated_at_id_idx",
+			Table:   table,
+			Columns: recentColumns,
+		})
+	}
+
+	return indexes
+}
+
+// FieldIndexes are the indexes of the fields alone. Foreign keys, which
+// include the parent id, are always indexed, since Postgres doesn't index
+// the referencing side. Unique fields already have the index backing their
+// constraint.
+func (i Input) FieldIndexes() []TableIndex {
+	table := i.Resource.UnderscorePlural()
+	indexes := []TableIndex{}
+
+	for _, field := range i.Fields {
+		if field.Unique || (!field.Index && field.Type != FieldTypeReferences) {
+			continue
+		}
+
+		index := TableIndex{
+			Name:    table + "_" + field.Name.String() + "_idx",
+			Table:   table,
+			Columns: field.Name.String(),
+		}
+
+		// arrays and json are queried by containment, which btree can't serve
+		if field.Array || field.Type == FieldTypeJSON {
+			index.Method = "gin"
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	return indexes
+}
+
+// UniqueConstraints are the constraints declared with unique_together, over
+// the field and the fields it lists.
+func (i Input) UniqueConstraints() []TableConstraint {
+	table := i.Resource.UnderscorePlural()
+	constraints := []TableConstraint{}
+
+	for _, field := range i.Fields {
+		if len(field.UniqueTogether) == 0 {
+			continue
+		}
+
+		columns := lo.Uniq(append([]string{field.Name.String()}, field.UniqueTogether...))
+
+		constraints = append(constraints, TableConstraint{
+			Name:    table + "_" + strings.Join(columns, "_") + "_key",
+			Columns: columns,
+		})
+	}
+
+	return constraints
+}
+
+// ensureConstraintFields checks that the fields listed in unique_together are
+// among the resource's columns.
+func (i Input) ensureConstraintFields(columns []string) error {
+	for _, field := range i.Fields {
+		for _, other := range field.UniqueTogether {
+			if !lo.Contains(columns, other) {
+				return fmt.Errorf("%s.%s: %s: %w", i.Resource, field.Name, other, ErrUnknownConstraintField)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (i Input) columnNames() []string {
+	return lo.Map(i.Fields, func(f InputField, _ int) string { return f.Name.String() })
+}
@@ -0,0 +1,341 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/samber/lo"
+)
+
+var ErrModuleNotFound = errors.New("module path not found in go.mod")
+
+// TypescriptInterface is a TypeScript interface generated from a Go struct.
+type TypescriptInterface struct {
+	Name       string
+	Properties []TypescriptProperty
+}
+
+type TypescriptProperty struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
+func validJSONType(jsonType string) bool {
+	importPath, typeName := splitJSONType(jsonType)
+
+	return importPath != "" && token.IsIdentifier(typeName) && token.IsExported(typeName)
+}
+
+// splitJSONType splits github.com/acme/app/internal/types.Settings into its
+// import path and type name. The separating dot is the first one after the
+// last slash, since the host part of the path has dots of its own.
+func splitJSONType(jsonType string) (string, string) {
+	lastSlash := strings.LastIndex(jsonType, "/")
+
+	dot := strings.Index(jsonType[lastSlash+1:], ".")
+	if dot == -1 {
+		return "", ""
+	}
+
+	dot += lastSlash + 1
+
+	return jsonType[:dot], jsonType[dot+1:]
+}
+
+// defaultJSONTypeName is the struct oxgen generates for a json field that
+// doesn't name one.
+func (f InputField) defaultJSONTypeName() string {
+	return f.Resource.CamelcaseSingular() + strcase.ToCamel(f.Name.String())
+}
+
+// JSONTypeName is the name of the Go type, and of the TypeScript interface,
+// that a json field holds.
+func (f InputField) JSONTypeName() string {
+	if f.JSONType == "" {
+		return f.defaultJSONTypeName()
+	}
+
+	_, typeName := splitJSONType(f.JSONType)
+
+	return typeName
+}
+
+// JSONImportPath is the import path of the package declaring JSONTypeName.
+func (f InputField) JSONImportPath() string {
+	importPath, _ := splitJSONType(f.JSONType)
+
+	return importPath
+}
+
+func (f InputField) jsonGoType() string {
+	if f.JSONType == "" {
+		return "types." + f.defaultJSONTypeName()
+	}
+
+	return path.Base(f.JSONImportPath()) + "." + f.JSONTypeName()
+}
+
+// JSONTypesFrontendModel declares the TypeScript interfaces for a json field.
+// Until the Go struct can be read, the interface accepts any properties.
+func (f InputField) JSONTypesFrontendModel() string {
+	if f.Type != FieldTypeJSON {
+		return ""
+	}
+
+	interfaces := f.JSONShape
+	if len(interfaces) == 0 {
+		interfaces = []TypescriptInterface{{Name: f.JSONTypeName()}}
+	}
+
+	declarations := lo.Map(interfaces, func(iface TypescriptInterface, _ int) string {
+		lines := []string{"export interface " + iface.Name + " {"}
+
+		for _, property := range iface.Properties {
+			optional := ""
+			if property.Optional {
+				optional = "?"
+			}
+
+			lines = append(lines, "  "+property.Name+optional+": "+property.Type+";")
+		}
+
+		if len(iface.Properties) == 0 {
+			lines = append(lines, "  [key: string]: unknown;")
+		}
+
+		return strings.Join(append(lines, "}"), "\n")
+	})
+
+	return strings.Join(declarations, "\n\n")
+}
+
+// modulePath reads the module path from the project's go.mod.
+func (s *Service) modulePath(workspaceFolder string) (string, error) {
+	content, err := s.readFile(filepath.Join(workspaceFolder, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	match := regexp.MustCompile(`(?m)^module\s+(\S+)`).FindSubmatch(content)
+	if match == nil {
+		return "", ErrModuleNotFound
+	}
+
+	return string(match[1]), nil
+}
+
+func hasJSONFields(input Input) bool {
+	return lo.SomeBy(input.Fields, func(field InputField) bool { return field.Type == FieldTypeJSON })
+}
+
+// resolveJSONTypes gives every json field its full Go type, defaulting to a
+// struct in the project's internal/types package, and reads the struct to
+// build the matching TypeScript interfaces.
+func (s *Service) resolveJSONTypes(input Input) (Input, error) {
+	if !hasJSONFields(input) {
+		return input, nil
+	}
+
+	module, err := s.modulePath(input.WorkspaceFolder)
+	if err != nil {
+		return Input{}, err
+	}
+
+	fields := append([]InputField{}, input.Fields...)
+
+	for i, field := range fields {
+		if field.Type != FieldTypeJSON {
+			continue
+		}
+
+		if field.JSONType == "" {
+			fields[i].JSONType = module + "/internal/types." + field.defaultJSONTypeName()
+		}
+
+		importPath := fields[i].JSONImportPath()
+		if importPath != module && !strings.HasPrefix(importPath, module+"/") {
+			continue
+		}
+
+		packageFolder := filepath.Join(input.WorkspaceFolder, filepath.FromSlash(strings.TrimPrefix(importPath, module)))
+
+		if fields[i].JSONShape, err = s.typescriptInterfaces(packageFolder, fields[i].JSONTypeName()); err != nil {
+			return Input{}, err
+		}
+	}
+
+	input.Fields = fields
+
+	return input, nil
+}
+
+// typescriptInterfaces converts the named struct, and the structs of its
+// package that it refers to, into TypeScript interfaces. It returns nil if the
+// package doesn't declare the struct.
+func (s *Service) typescriptInterfaces(packageFolder string, typeName string) ([]TypescriptInterface, error) {
+	paths, err := s.globFiles(packageFolder, "*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	structs := map[string]*ast.StructType{}
+
+	for _, filePath := range paths {
+		if strings.HasSuffix(filePath, "_test.go") {
+			continue
+		}
+
+		content, err := s.readFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, content, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filePath, err)
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			if typeSpec, isTypeSpec := node.(*ast.TypeSpec); isTypeSpec {
+				if structType, isStruct := typeSpec.Type.(*ast.StructType); isStruct {
+					structs[typeSpec.Name.Name] = structType
+				}
+			}
+
+			return true
+		})
+	}
+
+	if _, found := structs[typeName]; !found {
+		return nil, nil
+	}
+
+	interfaces := []TypescriptInterface{}
+	pending := []string{typeName}
+	seen := map[string]bool{typeName: true}
+
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+
+		iface := TypescriptInterface{Name: name}
+
+		for _, field := range structs[name].Fields.List {
+			property, ok := typescriptProperty(field, structs)
+			if !ok {
+				continue
+			}
+
+			iface.Properties = append(iface.Properties, property)
+
+			for _, referenced := range referencedStructs(field.Type, structs) {
+				if !seen[referenced] {
+					seen[referenced] = true
+					pending = append(pending, referenced)
+				}
+			}
+		}
+
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+// typescriptProperty describes a struct field the way encoding/json would
+// encode it.
+func typescriptProperty(field *ast.Field, structs map[string]*ast.StructType) (TypescriptProperty, bool) {
+	if len(field.Names) != 1 || !field.Names[0].IsExported() {
+		return TypescriptProperty{}, false
+	}
+
+	property := TypescriptProperty{Name: field.Names[0].Name}
+
+	if field.Tag != nil {
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get("json")
+		options := strings.Split(tag, ",")
+
+		if options[0] == "-" {
+			return TypescriptProperty{}, false
+		}
+
+		if options[0] != "" {
+			property.Name = options[0]
+		}
+
+		property.Optional = lo.Contains(options[1:], "omitempty")
+	}
+
+	if _, isPointer := field.Type.(*ast.StarExpr); isPointer {
+		property.Optional = true
+	}
+
+	property.Type = typescriptTypeOf(field.Type, structs)
+
+	return property, true
+}
+
+//nolint:cyclop
+func typescriptTypeOf(expr ast.Expr, structs map[string]*ast.StructType) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return typescriptTypeOf(expr.X, structs)
+	case *ast.ArrayType:
+		if ident, isIdent := expr.Elt.(*ast.Ident); isIdent && ident.Name == "byte" {
+			return "string"
+		}
+
+		return typescriptTypeOf(expr.Elt, structs) + "[]"
+	case *ast.MapType:
+		return "Record<string, " + typescriptTypeOf(expr.Value, structs) + ">"
+	case *ast.SelectorExpr:
+		if expr.Sel.Name == "Time" {
+			return "string"
+		}
+
+		return "unknown"
+	case *ast.Ident:
+		switch expr.Name {
+		case "string":
+			return "string"
+		case "bool":
+			return "boolean"
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+			return "number"
+		}
+
+		if _, found := structs[expr.Name]; found {
+			return expr.Name
+		}
+
+		return "unknown"
+	default:
+		return "unknown"
+	}
+}
+
+func referencedStructs(expr ast.Expr, structs map[string]*ast.StructType) []string {
+	referenced := []string{}
+
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if ident, isIdent := node.(*ast.Ident); isIdent {
+			if _, found := structs[ident.Name]; found {
+				referenced = append(referenced, ident.Name)
+			}
+		}
+
+		return true
+	})
+
+	return referenced
+}
@@ -0,0 +1,457 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var (
+	ErrServiceRequired = errors.New("service is required for resources missing from the manifest")
+	ErrServiceMismatch = errors.New("service does not match the manifest")
+)
+
+const endOfFileAnchor = "<end of file>"
+
+// Manifest records every resource oxgen generated in a project. It is kept in
+// .oxgen/manifest.json, and is what later commands consult to find out which
+// service a resource belongs to and which fields it has.
+type Manifest struct {
+	Resources []ManifestResource `json:"resources"`
+	Relations []ManifestRelation `json:"relations,omitempty"`
+}
+
+type ManifestResource struct {
+	Service        string          `json:"service"`
+	Resource       string          `json:"resource"`
+	Parent         string          `json:"parent,omitempty"`
+	Nested         bool            `json:"nested,omitempty"`
+	SoftDelete     bool            `json:"soft_delete,omitempty"`
+	ID             IDStrategy      `json:"id,omitempty"`
+	Pagination     Pagination      `json:"pagination,omitempty"`
+	SearchField    string          `json:"search_field,omitempty"`
+	FullTextFields []string        `json:"full_text_fields,omitempty"`
+	Bulk           bool            `json:"bulk,omitempty"`
+	UpsertKey      string          `json:"upsert_key,omitempty"`
+	Fields         []ManifestField `json:"fields"`
+	ManifestChanges
+}
+
+// ManifestRelation is a relation generated between two resources.
+type ManifestRelation struct {
+	Kind  RelationKind `json:"kind"`
+	Left  string       `json:"left"`
+	Right string       `json:"right"`
+	ManifestChanges
+}
+
+// ManifestChanges are the files a resource or relation created, the existing
+// files it modified, and where its templates were injected into them.
+type ManifestChanges struct {
+	Files    []string         `json:"files"`
+	Modified []string         `json:"modified_files"`
+	Anchors  []ManifestAnchor `json:"anchors"`
+}
+
+type ManifestField struct {
+	Name           string     `json:"name"`
+	Type           FieldType  `json:"type"`
+	Default        string     `json:"default,omitempty"`
+	Values         []string   `json:"values,omitempty"`
+	Table          string     `json:"table,omitempty"`
+	Required       bool       `json:"required,omitempty"`
+	Unique         bool       `json:"unique,omitempty"`
+	NotNull        bool       `json:"not_null,omitempty"`
+	Updateable     bool       `json:"updateable,omitempty"`
+	Precision      int        `json:"precision,omitempty"`
+	Scale          int        `json:"scale,omitempty"`
+	GoType         string     `json:"go_type,omitempty"`
+	Array          bool       `json:"array,omitempty"`
+	Includable     bool       `json:"includable,omitempty"`
+	Index          bool       `json:"index,omitempty"`
+	UniqueTogether []string   `json:"unique_together,omitempty"`
+	Check          string     `json:"check,omitempty"`
+	TableID        IDStrategy `json:"table_id,omitempty"`
+}
+
+// ManifestAnchor is a line in an existing file that a template was injected
+// next to.
+type ManifestAnchor struct {
+	File     string `json:"file"`
+	Anchor   string `json:"anchor"`
+	Template string `json:"template"`
+}
+
+func manifestPath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, ".oxgen", "manifest.json")
+}
+
+// LoadManifest reads the project manifest. A project without one has an empty
+// manifest.
+func (s *Service) LoadManifest(workspaceFolder string) (Manifest, error) {
+	manifest := Manifest{}
+
+	if !s.fileExists(manifestPath(workspaceFolder)) {
+		return manifest, nil
+	}
+
+	content, err := s.readFile(manifestPath(workspaceFolder))
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	if err = json.Unmarshal(content, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+func (s *Service) saveManifest(workspaceFolder string, manifest Manifest) error {
+	if err := s.ensureFolderExists(filepath.Join(workspaceFolder, ".oxgen")); err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	return s.writeFile(manifestPath(workspaceFolder), append(content, '\n'))
+}
+
+// Find returns the manifest entry for a resource.
+func (m Manifest) Find(resource string) (ManifestResource, bool) {
+	for _, entry := range m.Resources {
+		if entry.Resource == resource {
+			return entry, true
+		}
+	}
+
+	return ManifestResource{}, false
+}
+
+// softDeleted is true if a resource was generated with --soft-delete, so
+// queries joining it must skip deleted rows.
+func (m Manifest) softDeleted(resource TemplateName) bool {
+	entry, found := m.Find(resource.String())
+
+	return found && entry.SoftDelete
+}
+
+// key is the key strategy of a resource's table. Resources missing from the
+// manifest have uuid keys.
+func (m Manifest) key(resource TemplateName) IDStrategy {
+	entry, _ := m.Find(resource.String())
+
+	return entry.ID.orDefault()
+}
+
+// FindRelation returns the manifest entry for a relation between two
+// resources, whichever side it was generated from.
+func (m Manifest) FindRelation(kind RelationKind, left string, right string) (ManifestRelation, bool) {
+	for _, entry := range m.Relations {
+		if entry.Kind == kind && ((entry.Left == left && entry.Right == right) || (entry.Left == right && entry.Right == left)) {
+			return entry, true
+		}
+	}
+
+	return ManifestRelation{}, false
+}
+
+func (m *Manifest) put(entry ManifestResource) {
+	for i := range m.Resources {
+		if m.Resources[i].Resource == entry.Resource {
+			m.Resources[i] = entry
+
+			return
+		}
+	}
+
+	m.Resources = append(m.Resources, entry)
+}
+
+func (m *Manifest) remove(resource string) {
+	resources := []ManifestResource{}
+
+	for _, entry := range m.Resources {
+		if entry.Resource != resource {
+			resources = append(resources, entry)
+		}
+	}
+
+	m.Resources = resources
+}
+
+// removeRelations forgets the relations a resource is part of.
+func (m *Manifest) removeRelations(resource string) {
+	m.Relations = lo.Reject(m.Relations, func(entry ManifestRelation, _ int) bool {
+		return entry.Left == resource || entry.Right == resource
+	})
+}
+
+// Input rebuilds the input the resource was generated from.
+func (r ManifestResource) Input(workspaceFolder string) Input {
+	input := Input{
+		WorkspaceFolder: workspaceFolder,
+		Service:         TemplateName(r.Service),
+		Resource:        TemplateName(r.Resource),
+		SearchField:     r.SearchField,
+		FullTextFields:  r.FullTextFields,
+		HasSearch:       r.SearchField != "" || len(r.FullTextFields) > 0,
+		Bulk:            r.Bulk,
+		UpsertKey:       r.UpsertKey,
+		Nested:          r.Nested,
+		SoftDelete:      r.SoftDelete,
+		ID:              r.ID,
+		Pagination:      r.Pagination,
+	}
+
+	if r.Parent != "" {
+		parentName := TemplateName(r.Parent)
+		input.Parent = &parentName
+		input.ParentID = parentKey(input.Parent, r.Fields)
+	}
+
+	for _, field := range r.Fields {
+		input.Fields = append(input.Fields, InputField{
+			Service:        input.Service,
+			Resource:       input.Resource,
+			Name:           TemplateName(field.Name),
+			Type:           field.Type,
+			Required:       field.Required,
+			Default:        field.Default,
+			EnumValues:     field.Values,
+			Table:          field.Table,
+			Unique:         field.Unique,
+			NotNull:        field.NotNull,
+			Updateable:     field.Updateable,
+			Precision:      field.Precision,
+			Scale:          field.Scale,
+			JSONType:       field.GoType,
+			Array:          field.Array,
+			Includable:     field.Includable,
+			Index:          field.Index,
+			Check:          field.Check,
+			UniqueTogether: field.UniqueTogether,
+			TableID:        field.TableID,
+		})
+	}
+
+	return input.withResourceOptions()
+}
+
+func manifestFields(fields []InputField) []ManifestField {
+	manifestFields := []ManifestField{}
+
+	for _, field := range fields {
+		manifestFields = append(manifestFields, ManifestField{
+			Name:           field.Name.String(),
+			Type:           field.Type,
+			Default:        field.Default,
+			Values:         field.EnumValues,
+			Table:          field.Table,
+			Required:       field.Required,
+			Unique:         field.Unique,
+			NotNull:        field.NotNull,
+			Updateable:     field.Updateable,
+			Precision:      field.Precision,
+			Scale:          field.Scale,
+			GoType:         field.JSONType,
+			Array:          field.Array,
+			Includable:     field.Includable,
+			Index:          field.Index,
+			Check:          field.Check,
+			UniqueTogether: field.UniqueTogether,
+			TableID:        field.TableID,
+		})
+	}
+
+	return manifestFields
+}
+
+// resolveInput fills in the service and parent of a resource from the
+// manifest, for commands that act on resources which already exist. The
+// manifest entry is returned as well, or nil if the resource is not in it.
+func (s *Service) resolveInput(input Input) (Input, *ManifestResource, error) {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return Input{}, nil, err
+	}
+
+	entry, found := manifest.Find(input.Resource.String())
+	if !found {
+		if input.Service == "" {
+			return Input{}, nil, fmt.Errorf("%s: %w", input.Resource, ErrServiceRequired)
+		}
+
+		return input, nil, nil
+	}
+
+	if input.Service != "" && input.Service.String() != entry.Service {
+		return Input{}, nil, fmt.Errorf("%s belongs to %s: %w", input.Resource, entry.Service, ErrServiceMismatch)
+	}
+
+	input.Service = TemplateName(entry.Service)
+
+	if entry.Parent != "" {
+		parentName := TemplateName(entry.Parent)
+		input.Parent = &parentName
+		input.ParentID = parentKey(input.Parent, entry.Fields)
+	}
+
+	input.Nested = entry.Nested
+	input.SoftDelete = entry.SoftDelete
+	input.ID = entry.ID
+	input.Pagination = entry.Pagination
+
+	for i := range input.Fields {
+		input.Fields[i].Service = input.Service
+	}
+
+	return input.withResourceOptions(), &entry, nil
+}
+
+// recordResource stores what the current run generated for the resource in
+// the manifest.
+func (s *Service) recordResource(input Input) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	entry := ManifestResource{
+		Service:        input.Service.String(),
+		Resource:       input.Resource.String(),
+		Nested:         input.Nested,
+		SoftDelete:     input.SoftDelete,
+		ID:             input.ID,
+		Pagination:     input.Pagination,
+		SearchField:    input.SearchField,
+		FullTextFields: input.FullTextFields,
+		Bulk:           input.Bulk,
+		UpsertKey:      input.UpsertKey,
+		Fields:         manifestFields(input.Fields),
+	}
+
+	if input.Parent != nil {
+		entry.Parent = input.Parent.String()
+	}
+
+	s.addRunToEntry(input.WorkspaceFolder, &entry.ManifestChanges)
+	manifest.put(entry)
+
+	return s.saveManifest(input.WorkspaceFolder, manifest)
+}
+
+// recordFields adds fields to a resource that is already in the manifest, and
+// refreshes its pristine files. Resources generated before the manifest
+// existed are left out of it.
+func (s *Service) recordFields(ctx context.Context, input Input) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	entry, found := manifest.Find(input.Resource.String())
+	if !found {
+		return nil
+	}
+
+	entry.Fields = append(entry.Fields, manifestFields(input.Fields)...)
+	s.addRunToEntry(input.WorkspaceFolder, &entry.ManifestChanges)
+	manifest.put(entry)
+
+	if err = s.saveManifest(input.WorkspaceFolder, manifest); err != nil {
+		return err
+	}
+
+	return s.storePristineFiles(ctx, entry.Input(input.WorkspaceFolder))
+}
+
+// recordRegenerated adds files that a regeneration created to the manifest.
+func (s *Service) recordRegenerated(input Input) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	entry, found := manifest.Find(input.Resource.String())
+	if !found {
+		return nil
+	}
+
+	s.addRunToEntry(input.WorkspaceFolder, &entry.ManifestChanges)
+	manifest.put(entry)
+
+	return s.saveManifest(input.WorkspaceFolder, manifest)
+}
+
+func (s *Service) forgetResource(input Input) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	entry, found := manifest.Find(input.Resource.String())
+	if !found {
+		return nil
+	}
+
+	if err = s.removePristineFiles(input.WorkspaceFolder, entry); err != nil {
+		return err
+	}
+
+	manifest.remove(input.Resource.String())
+	manifest.removeRelations(input.Resource.String())
+
+	return s.saveManifest(input.WorkspaceFolder, manifest)
+}
+
+// addRunToEntry records the files the current run created and modified,
+// leaving out oxgen's own files.
+func (s *Service) addRunToEntry(workspaceFolder string, entry *ManifestChanges) {
+	relative := func(path string) string {
+		if rel, err := filepath.Rel(workspaceFolder, path); err == nil {
+			return filepath.ToSlash(rel)
+		}
+
+		return path
+	}
+
+	for _, path := range s.workspace.run.created {
+		if !strings.HasPrefix(relative(path), ".oxgen/") {
+			entry.Files = appendUnique(entry.Files, relative(path))
+		}
+	}
+
+	for _, path := range s.workspace.run.modified {
+		if !strings.HasPrefix(relative(path), ".oxgen/") && !lo.Contains(entry.Files, relative(path)) {
+			entry.Modified = appendUnique(entry.Modified, relative(path))
+		}
+	}
+
+	for _, anchor := range s.workspace.run.anchors {
+		anchor.File = relative(anchor.File)
+
+		// regenerating injects the same templates next to the same lines
+		if !lo.Contains(entry.Anchors, anchor) {
+			entry.Anchors = append(entry.Anchors, anchor)
+		}
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
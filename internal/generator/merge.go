@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	conflictStart  = "<<<<<<< current\n"
+	conflictMiddle = "=======\n"
+	conflictEnd    = ">>>>>>> regenerated\n"
+)
+
+// mergeThreeWay merges the changes made between base and ours with those made
+// between base and theirs, line by line. Where both sides changed the same
+// lines differently, both versions are kept between conflict markers. It
+// returns the merged content and the number of conflicts.
+//
+//nolint:cyclop
+func mergeThreeWay(base []byte, ours []byte, theirs []byte) ([]byte, int) {
+	baseLines := mergeLines(base)
+	ourLines := mergeLines(ours)
+	theirLines := mergeLines(theirs)
+
+	ourMatches := matchedLines(baseLines, ourLines)
+	theirMatches := matchedLines(baseLines, theirLines)
+
+	merged := []string{}
+	conflicts := 0
+
+	baseIndex, ourIndex, theirIndex := 0, 0, 0
+
+	for {
+		// find the next base line that is unchanged on both sides
+		stable := baseIndex
+		for stable < len(baseLines) && (ourMatches[stable] < ourIndex || theirMatches[stable] < theirIndex) {
+			stable++
+		}
+
+		ourEnd, theirEnd := len(ourLines), len(theirLines)
+		if stable < len(baseLines) {
+			ourEnd, theirEnd = ourMatches[stable], theirMatches[stable]
+		}
+
+		baseChunk := baseLines[baseIndex:stable]
+		ourChunk := ourLines[ourIndex:ourEnd]
+		theirChunk := theirLines[theirIndex:theirEnd]
+
+		switch {
+		case equalLines(ourChunk, baseChunk):
+			merged = append(merged, theirChunk...)
+		case equalLines(theirChunk, baseChunk), equalLines(ourChunk, theirChunk):
+			merged = append(merged, ourChunk...)
+		default:
+			conflicts++
+
+			merged = append(merged, conflictStart)
+			merged = append(merged, terminatedLines(ourChunk)...)
+			merged = append(merged, conflictMiddle)
+			merged = append(merged, terminatedLines(theirChunk)...)
+			merged = append(merged, conflictEnd)
+		}
+
+		if stable == len(baseLines) {
+			break
+		}
+
+		merged = append(merged, baseLines[stable])
+		baseIndex, ourIndex, theirIndex = stable+1, ourEnd+1, theirEnd+1
+	}
+
+	return []byte(strings.Join(merged, "")), conflicts
+}
+
+// matchedLines maps each line of base to the line it matches in other, or -1.
+func matchedLines(base []string, other []string) []int {
+	matches := make([]int, len(base))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	for _, block := range difflib.NewMatcherWithJunk(base, other, false, nil).GetMatchingBlocks() {
+		for i := 0; i < block.Size; i++ {
+			matches[block.A+i] = block.B + i
+		}
+	}
+
+	return matches
+}
+
+func mergeLines(content []byte) []string {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+func terminatedLines(lines []string) []string {
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		return lines
+	}
+
+	terminated := append([]string{}, lines...)
+	terminated[len(terminated)-1] += "\n"
+
+	return terminated
+}
+
+func equalLines(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
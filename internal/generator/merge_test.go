@@ -0,0 +1,100 @@
+package generator
+
+import "testing"
+
+func TestMergeThreeWay(t *testing.T) {
+	tests := []struct {
+		name      string
+		base      string
+		ours      string
+		theirs    string
+		merged    string
+		conflicts int
+	}{
+		{
+			name:   "unchanged",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nb\nc\n",
+		},
+		{
+			name:   "only regenerated",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\n",
+			theirs: "a\nB\nc\n",
+			merged: "a\nB\nc\n",
+		},
+		{
+			name:   "only edited",
+			base:   "a\nb\nc\n",
+			ours:   "a\nb\nc\nd\n",
+			theirs: "a\nb\nc\n",
+			merged: "a\nb\nc\nd\n",
+		},
+		{
+			name:   "clean merge",
+			base:   "a\nb\nc\nd\ne\n",
+			ours:   "A\nb\nc\nd\ne\n",
+			theirs: "a\nb\nc\nd\nE\n",
+			merged: "A\nb\nc\nd\nE\n",
+		},
+		{
+			name:   "both sides made the same change",
+			base:   "a\nb\nc\n",
+			ours:   "a\nB\nc\n",
+			theirs: "a\nB\nc\n",
+			merged: "a\nB\nc\n",
+		},
+		{
+			name:      "both sides changed",
+			base:      "a\nb\nc\n",
+			ours:      "a\nours\nc\n",
+			theirs:    "a\ntheirs\nc\n",
+			merged:    "a\n<<<<<<< current\nours\n=======\ntheirs\n>>>>>>> regenerated\nc\n",
+			conflicts: 1,
+		},
+		{
+			name:      "both sides changed a last line without a newline",
+			base:      "a\nb",
+			ours:      "a\nours",
+			theirs:    "a\ntheirs",
+			merged:    "a\n<<<<<<< current\nours\n=======\ntheirs\n>>>>>>> regenerated\n",
+			conflicts: 1,
+		},
+		{
+			name:   "no base, new file",
+			ours:   "",
+			theirs: "a\nb\n",
+			merged: "a\nb\n",
+		},
+		{
+			name:   "no base, same content",
+			ours:   "a\nb\n",
+			theirs: "a\nb\n",
+			merged: "a\nb\n",
+		},
+		{
+			name:      "no base, different content",
+			ours:      "a\n",
+			theirs:    "b\n",
+			merged:    "<<<<<<< current\na\n=======\nb\n>>>>>>> regenerated\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var base []byte
+			if test.base != "" {
+				base = []byte(test.base)
+			}
+
+			merged, conflicts := mergeThreeWay(base, []byte(test.ours), []byte(test.theirs))
+
+			if string(merged) != test.merged || conflicts != test.conflicts {
+				t.Errorf("expected %q with %d conflicts, got %q with %d", test.merged, test.conflicts, merged, conflicts)
+			}
+		})
+	}
+}
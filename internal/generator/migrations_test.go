@@ -0,0 +1,281 @@
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+)
+
+// TestMigrationsRoundTrip checks that the down migration of every migration a
+// resource's lifecycle writes reverses its up migration: each object the up
+// migration creates is dropped by exactly one down statement, after the
+// objects that depend on it.
+func TestMigrationsRoundTrip(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	post := testInput(t, workspaceFolder, "Post",
+		"title:string",
+		"slug:string:unique:not_null",
+		"status:enum:values=draft,published",
+		"views:int:index",
+	)
+	post.SearchField = "title"
+	post.HasSearch = true
+	post.SoftDelete = true
+
+	if err := New().Generate(context.Background(), post); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	if err := New().AddFields(context.Background(), testInput(t, workspaceFolder, "Post",
+		"kind:enum:values=article,note:default=note",
+		"code:string:unique",
+		"rank:int:index",
+	)); err != nil {
+		t.Fatalf("failed to add fields: %v", err)
+	}
+
+	if err := New().Destroy(context.Background(), testInput(t, workspaceFolder, "Post")); err != nil {
+		t.Fatalf("failed to destroy: %v", err)
+	}
+
+	upMigrations, err := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*.up.sql"))
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+
+	//nolint:gomnd
+	if len(upMigrations) != 3 {
+		t.Fatalf("expected create, add and drop migrations, got %v", upMigrations)
+	}
+
+	for _, upMigration := range upMigrations {
+		name := filepath.Base(upMigration)
+
+		t.Run(name, func(t *testing.T) {
+			upSQL := readTestFile(t, upMigration)
+			downSQL := readTestFile(t, strings.TrimSuffix(upMigration, ".up.sql")+".down.sql")
+
+			// a drop migration creates what it drops on the way down
+			if strings.Contains(name, "_drop_") {
+				upSQL, downSQL = downSQL, upSQL
+			}
+
+			checkReverses(t, sqlStatements(upSQL), sqlStatements(downSQL))
+		})
+	}
+}
+
+func TestSQLStatementsObject(t *testing.T) {
+	tests := []struct {
+		statement string
+		object    string
+		creates   bool
+	}{
+		{"CREATE EXTENSION IF NOT EXISTS moddatetime", "", true},
+		{"CREATE TYPE post_status AS ENUM ('a', 'b')", "type post_status", true},
+		{"DROP TYPE post_status", "type post_status", false},
+		{"CREATE TABLE posts (id uuid PRIMARY KEY)", "table posts", true},
+		{"DROP TABLE posts", "table posts", false},
+		{"CREATE TRIGGER posts_updated_at BEFORE UPDATE ON posts FOR EACH ROW EXECUTE FUNCTION moddatetime(updated_at)", "trigger posts_updated_at on posts", true},
+		{"DROP TRIGGER posts_updated_at ON posts", "trigger posts_updated_at on posts", false},
+		{"CREATE INDEX posts_title_idx ON posts USING gin (title gin_trgm_ops)", "index posts_title_idx", true},
+		{"DROP INDEX posts_title_idx", "index posts_title_idx", false},
+		{"ALTER TABLE posts ADD COLUMN code text UNIQUE, ADD CONSTRAINT posts_a_b_key UNIQUE (a, b)", "columns of posts: code, constraint posts_a_b_key", true},
+		{"ALTER TABLE posts DROP CONSTRAINT posts_a_b_key, DROP COLUMN code", "columns of posts: code, constraint posts_a_b_key", false},
+	}
+
+	for _, test := range tests {
+		object, creates := sqlObject(test.statement)
+		if object != test.object || creates != test.creates {
+			t.Errorf("%q: expected %q (creates: %v), got %q (creates: %v)", test.statement, test.object, test.creates, object, creates)
+		}
+	}
+}
+
+// checkReverses checks that the down statements drop the objects the up
+// statements create, dependents first. Extensions are shared by every table,
+// so they are left installed.
+func checkReverses(t *testing.T, upStatements []string, downStatements []string) {
+	t.Helper()
+
+	created := []string{}
+	dropOrder := map[string]int{}
+
+	for _, statement := range upStatements {
+		object, creates := sqlObject(statement)
+		if !creates {
+			t.Errorf("expected up statement to create, got %q", statement)
+		}
+
+		if object != "" {
+			created = append(created, object)
+		}
+	}
+
+	for n, statement := range downStatements {
+		object, creates := sqlObject(statement)
+		if creates || object == "" {
+			t.Errorf("expected down statement to drop, got %q", statement)
+
+			continue
+		}
+
+		if _, found := dropOrder[object]; found {
+			t.Errorf("%s is dropped twice", object)
+		}
+
+		dropOrder[object] = n
+	}
+
+	for object := range dropOrder {
+		if !slices.Contains(created, object) {
+			t.Errorf("%s is dropped but was not created", object)
+		}
+	}
+
+	for _, object := range created {
+		if _, found := dropOrder[object]; found {
+			continue
+		}
+
+		// dropping a table drops the indexes, triggers and columns on it
+		table, droppedWith := lo.Find(created, func(table string) bool {
+			_, dropped := dropOrder[table]
+
+			return dropped && strings.HasPrefix(table, "table ") && dependsOn(upStatements, object, table)
+		})
+		if !droppedWith {
+			t.Errorf("%s is created but not dropped, down statements: %v", object, downStatements)
+
+			continue
+		}
+
+		dropOrder[object] = dropOrder[table]
+	}
+
+	// an object whose statement names another created before it depends on it
+	for i, object := range created {
+		for _, dependent := range created[i+1:] {
+			if !dependsOn(upStatements, dependent, object) {
+				continue
+			}
+
+			if dropOrder[dependent] > dropOrder[object] {
+				t.Errorf("%s is dropped before %s, which depends on it", object, dependent)
+			}
+		}
+	}
+}
+
+// dependsOn is true if the statement creating the dependent names the object.
+func dependsOn(statements []string, dependent string, object string) bool {
+	name := regexp.MustCompile(`\b` + regexp.QuoteMeta(objectName(object)) + `\b`)
+
+	for _, statement := range statements {
+		if created, _ := sqlObject(statement); created == dependent {
+			return name.MatchString(statement)
+		}
+	}
+
+	return false
+}
+
+// objectName is the name of an object, without its kind.
+func objectName(object string) string {
+	_, name, _ := strings.Cut(object, " ")
+
+	return name
+}
+
+// sqlStatements splits a migration into its statements, with their
+// whitespace collapsed.
+func sqlStatements(sql string) []string {
+	statements := []string{}
+
+	for _, statement := range strings.Split(sql, ";") {
+		statement = strings.Join(strings.Fields(statement), " ")
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements
+}
+
+// sqlObject is the object a statement creates or drops, e.g. "table posts",
+// and whether it creates it. The columns and constraints an ALTER TABLE adds
+// or drops are one object.
+//
+//nolint:gomnd
+func sqlObject(statement string) (string, bool) {
+	words := strings.Fields(strings.NewReplacer("IF NOT EXISTS ", "", "IF EXISTS ", "", "UNIQUE INDEX", "INDEX").Replace(statement))
+	if len(words) < 3 {
+		return "", false
+	}
+
+	creates := words[0] == "CREATE" || (words[0] == "ALTER" && strings.Contains(statement, " ADD "))
+
+	switch {
+	case words[1] == "EXTENSION":
+		return "", creates
+	case words[1] == "TYPE", words[1] == "TABLE" && words[0] != "ALTER", words[1] == "INDEX":
+		return strings.ToLower(words[1]) + " " + strings.TrimSuffix(words[2], "("), creates
+	case words[1] == "TRIGGER":
+		on := strings.Index(statement, " ON ")
+		table := strings.Fields(statement[on+len(" ON "):])[0]
+
+		return "trigger " + words[2] + " on " + table, creates
+	case words[0] == "ALTER" && words[1] == "TABLE":
+		parts := []string{}
+
+		for _, clause := range splitTopLevel(strings.Join(words[3:], " ")) {
+			clauseWords := strings.Fields(clause)
+			if len(clauseWords) < 2 {
+				continue
+			}
+
+			// ADD COLUMN x / DROP COLUMN x, ADD CONSTRAINT c / DROP CONSTRAINT c
+			if clauseWords[1] == "CONSTRAINT" {
+				parts = append(parts, "constraint "+clauseWords[2])
+			} else {
+				parts = append(parts, clauseWords[2])
+			}
+		}
+
+		sort.Strings(parts)
+
+		return "columns of " + words[2] + ": " + strings.Join(parts, ", "), creates
+	default:
+		return "", creates
+	}
+}
+
+// splitTopLevel splits on the commas that are outside of parentheses.
+func splitTopLevel(clauses string) []string {
+	parts := []string{}
+	depth := 0
+	start := 0
+
+	for n, char := range clauses {
+		switch char {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(clauses[start:n]))
+				start = n + 1
+			}
+		}
+	}
+
+	return append(parts, strings.TrimSpace(clauses[start:]))
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+var (
+	ErrNestedWithoutParent = errors.New("only resources with a parent can be nested")
+	ErrParentNotGenerated  = errors.New("parent resource was not generated by oxgen")
+)
+
+// Nested is true for fields of a resource whose routes are nested under its
+// parent's, e.g. /posts/:parent_id/comments/:id.
+func (f InputField) Nested() bool {
+	return f.NestedIn != nil
+}
+
+// IsParentField is true for the references field that ties a nested resource
+// to its parent. Its value comes from the route rather than the request body.
+func (i Input) IsParentField(field InputField) bool {
+	return i.Nested && i.Parent != nil && field.Name.String() == i.Parent.UnderscoreSingular()+"_id"
+}
+
+// LiveParentSQL leaves out the rows of a nested resource whose parent is soft
+// deleted, so that they are hidden along with it until it is restored or
+// purged. Listings check the parent with its FetchByIDs query instead.
+func (i Input) LiveParentSQL() string {
+	if !i.Nested || i.Parent == nil || !i.ParentSoftDelete {
+		return ""
+	}
+
+	return liveParentSQL(*i.Parent, "    ")
+}
+
+func (f InputField) LiveParentSQL() string {
+	if f.NestedIn == nil || !f.ParentSoftDelete {
+		return ""
+	}
+
+	return liveParentSQL(*f.NestedIn, "  ")
+}
+
+func liveParentSQL(parent TemplateName, indent string) string {
+	return "\n" + indent + "AND EXISTS (SELECT 1 FROM " + parent.UnderscorePlural() + " p WHERE p.id = t." + parent.UnderscoreSingular() + "_id AND p.deleted_at IS NULL)"
+}
+
+// ensureNestable checks that a nested resource's parent was generated, since
+// its FetchByIDs query is what the parent's existence is checked with.
+func (s *Service) ensureNestable(input Input) error {
+	if !input.Nested {
+		return nil
+	}
+
+	if input.Parent == nil {
+		return fmt.Errorf("%s: %w", input.Resource, ErrNestedWithoutParent)
+	}
+
+	presenterPath := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api", "presenter", input.Parent.UnderscoreSingular()+".go")
+	if !s.fileExists(presenterPath) {
+		return fmt.Errorf("%s: %w", *input.Parent, ErrParentNotGenerated)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestChildrenOfSoftDeletedParents(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	post := testInput(t, workspaceFolder, "Post", "title:string")
+	post.SoftDelete = true
+
+	if err := New().Generate(context.Background(), post); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	comment := testInput(t, workspaceFolder, "Comment", "body:string:updateable")
+	comment.Parent = &post.Resource
+	comment.Nested = true
+
+	if err := New().Generate(context.Background(), comment); err != nil {
+		t.Fatalf("failed to generate nested resource: %v", err)
+	}
+
+	// children that aren't nested still reference their parent's rows
+	like := testInput(t, workspaceFolder, "Like", "weight:int")
+	like.Parent = &post.Resource
+
+	if err := New().Generate(context.Background(), like); err != nil {
+		t.Fatalf("failed to generate child resource: %v", err)
+	}
+
+	queries := readTestFile(t, filepath.Join(workspaceFolder, "internal", "database", "queries.sql"))
+	liveParent := "AND EXISTS (SELECT 1 FROM posts p WHERE p.id = t.post_id AND p.deleted_at IS NULL)"
+
+	for _, query := range []string{"FetchCommentByID", "DeleteComment", "UpdateCommentBody"} {
+		if !strings.Contains(sqlQuery(queries, query), liveParent) {
+			t.Errorf("expected %s to leave out comments of deleted posts, got:\n%s", query, sqlQuery(queries, query))
+		}
+	}
+
+	if strings.Contains(sqlQuery(queries, "FetchLikeByID"), liveParent) {
+		t.Errorf("expected likes to be fetched by id alone, got:\n%s", sqlQuery(queries, "FetchLikeByID"))
+	}
+
+	purge := readTestFile(t, filepath.Join(workspaceFolder, "internal", "service", "blog", "purge_deleted_posts.go"))
+
+	for _, call := range []string{"tx.DeleteCommentsByPostIDs(ctx, deletedIDs)", "tx.DeleteLikesByPostIDs(ctx, deletedIDs)"} {
+		if !strings.Contains(purge, call) {
+			t.Errorf("expected purging posts to call %s, got:\n%s", call, purge)
+		}
+	}
+}
+
+// sqlQuery is the named query in queries.sql, up to the next one.
+func sqlQuery(queries string, name string) string {
+	_, query, found := strings.Cut(queries, "-- name: "+name+" ")
+	if !found {
+		return ""
+	}
+
+	query, _, _ = strings.Cut(query, "-- name: ")
+
+	return query
+}
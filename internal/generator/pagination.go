@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+var ErrInvalidPagination = errors.New("invalid pagination")
+
+// Pagination is how the recent and search listings of a resource are paged.
+// Offset pages are numbered and come with a total count. Cursor pages are
+// fetched after or before a position in the (updated_at, id) order, so their
+// queries stay on the index however deep the page, and they count nothing.
+// Full-text search pages are positions in the (rank, id) order instead.
+type Pagination string
+
+const (
+	PaginationOffset Pagination = "offset"
+	PaginationCursor Pagination = "cursor"
+)
+
+func ParsePagination(value string) (Pagination, error) {
+	switch Pagination(value) {
+	case "", PaginationOffset:
+		return PaginationOffset, nil
+	case PaginationCursor:
+		return PaginationCursor, nil
+	default:
+		return "", fmt.Errorf("%q: %w", value, ErrInvalidPagination)
+	}
+}
+
+func (i Input) CursorPagination() bool {
+	return i.Pagination == PaginationCursor
+}
+
+// listTemplate is the template of a recent or search method, in its cursor
+// variant for cursor paginated resources.
+func (i Input) listTemplate(name string) string {
+	if !i.CursorPagination() {
+		return name
+	}
+
+	return "cursor_" + name
+}
+
+func cursorTypeFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "service", "cursor.go")
+}
+
+func cursorHelpersFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "handler", "api", "cursors.go")
+}
+
+// ensureCursorHelpers adds the cursor type shared by the services, and the
+// handler functions encoding it, the first time a cursor paginated resource
+// is generated.
+func (s *Service) ensureCursorHelpers(input Input) error {
+	if !input.CursorPagination() {
+		return nil
+	}
+
+	files := map[string]string{
+		cursorTypeFilePath(input.WorkspaceFolder):    "cursor_type",
+		cursorHelpersFilePath(input.WorkspaceFolder): "cursor_helpers",
+	}
+
+	for path, templateName := range files {
+		if s.fileExists(path) {
+			continue
+		}
+
+		if err := s.ensureFileExists(path, templateName, input); err != nil {
+			return err
+		}
+
+		if err := s.runCommand(filepath.Dir(path), "goimports", "-w", filepath.Base(path)); err != nil {
+			return fmt.Errorf("failed running goimports: %w", err)
+		}
+	}
+
+	return s.ensureCursorRank(input)
+}
+
+// ensureCursorRank adds the rank to cursors added before ranked searches
+// were, the first time a resource with one is generated.
+func (s *Service) ensureCursorRank(input Input) error {
+	if !input.RankedSearch() {
+		return nil
+	}
+
+	path := cursorTypeFilePath(input.WorkspaceFolder)
+
+	content, err := s.readFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if bytes.Contains(content, []byte("\tRank ")) {
+		return nil
+	}
+
+	return s.injectIntoStruct(path, "Cursor", "cursor_rank_field", input)
+}
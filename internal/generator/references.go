@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// ReferenceName is what a references field points at, the field's name
+// without its _id suffix, e.g. author for author_id.
+func (f InputField) ReferenceName() TemplateName {
+	return TemplateName(strings.TrimSuffix(f.Name.String(), "_id"))
+}
+
+// ReferencedResource is the resource stored in the table a references field
+// points at.
+func (f InputField) ReferencedResource() TemplateName {
+	return TemplateName(f.Table)
+}
+
+// HasMany is true for fields that the referenced resource can list its
+// children by. A nested resource is already listed under its parent's routes.
+func (f InputField) HasMany() bool {
+	return f.Type == FieldTypeReferences && !f.isNestedParent()
+}
+
+func (f InputField) isNestedParent() bool {
+	return f.Nested() && f.Name.String() == f.NestedIn.UnderscoreSingular()+"_id"
+}
+
+// HasManySegment is the last segment of the route that lists the children of
+// a referenced record: /users/:id/posts for user_id, or
+// /users/:id/author_posts for author_id, which also points at users.
+func (f InputField) HasManySegment() string {
+	if f.ReferenceName().UnderscorePlural() == f.ReferencedResource().UnderscorePlural() {
+		return f.Resource.UnderscorePlural()
+	}
+
+	return f.ReferenceName().UnderscoreSingular() + "_" + f.Resource.UnderscorePlural()
+}
+
+// ReferenceIDColumn is the dbx struct field holding a references column.
+func (f InputField) ReferenceIDColumn() string {
+	dbxField := f.Name.CamelcaseSingular()
+
+	if strings.HasSuffix(dbxField, "Id") {
+		dbxField = dbxField[:len(dbxField)-2] + "ID"
+	}
+
+	return dbxField
+}
+
+// ReferenceIDField is the expression for the referenced id on a dbx record.
+// Nullable references are a null wrapper, which holds the zero key when unset.
+func (f InputField) ReferenceIDField() string {
+	if f.NotNull {
+		return f.ReferenceIDColumn()
+	}
+
+	return f.ReferenceIDColumn() + "." + f.PgType()
+}
+
+func (i Input) HasIncludes() bool {
+	return lo.SomeBy(i.Fields, func(field InputField) bool { return field.Includable })
+}
+
+// resolveReferences marks the references fields that point at another
+// generated resource as includable, since their records can then be loaded
+// with its FetchByIDs query and presented along with the resource. This is
+// decided when the resource is generated and kept in the manifest, so that
+// regenerating it renders the same handlers.
+func (s *Service) resolveReferences(input Input) Input {
+	fields := append([]InputField{}, input.Fields...)
+
+	for i, field := range fields {
+		if field.Type != FieldTypeReferences || field.ReferencedResource().CamelcaseSingular() == input.Resource.CamelcaseSingular() {
+			continue
+		}
+
+		presenterPath := filepath.Join(input.WorkspaceFolder, "internal", "handler", "api", "presenter", field.ReferencedResource().UnderscoreSingular()+".go")
+		fields[i].Includable = s.fileExists(presenterPath)
+	}
+
+	input.Fields = fields
+
+	return input
+}
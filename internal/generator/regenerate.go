@@ -0,0 +1,321 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Regenerate renders the files a resource owns with the current templates,
+// and merges them into the project. Hand edits made since the files were
+// generated are kept, and where they clash with template changes both
+// versions are written between conflict markers. It returns the files that
+// have conflicts. The resource's queries and their methods on
+// DatabaseProvider are rendered afresh, and sqlc is run again, so that the
+// merged files call what the current templates query.
+func (s *Service) Regenerate(ctx context.Context, input Input) ([]string, error) {
+	s.beginRun()
+
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, found := manifest.Find(input.Resource.String())
+	if !found {
+		return nil, fmt.Errorf("%s: %w", input.Resource, ErrResourceNotFound)
+	}
+
+	input = entry.Input(input.WorkspaceFolder)
+
+	conflicted, err := s.regenerate(ctx, input)
+	if err != nil {
+		if s.dryRun {
+			return nil, err
+		}
+
+		return nil, s.rollback(input, err)
+	}
+
+	return conflicted, nil
+}
+
+func (s *Service) regenerate(ctx context.Context, input Input) ([]string, error) {
+	// listings generated before filters were added lack their helpers
+	if err := s.ensureListHelpers(input); err != nil {
+		return nil, fmt.Errorf("failed adding list helpers: %w", err)
+	}
+
+	// and services generated before transactions were added lack the provider
+	if err := s.ensureTransactions(input); err != nil {
+		return nil, fmt.Errorf("failed adding database provider: %w", err)
+	}
+
+	if err := s.regenerateQueries(ctx, input); err != nil {
+		return nil, fmt.Errorf("failed regenerating queries: %w", err)
+	}
+
+	conflicted, err := s.mergeResourceFiles(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.recordRegenerated(input); err != nil {
+		return nil, fmt.Errorf("failed updating manifest: %w", err)
+	}
+
+	return conflicted, nil
+}
+
+// regenerateQueries replaces the resource's queries in queries.sql and its
+// methods on DatabaseProvider with the current templates' output, and runs
+// sqlc over them. Hand edits to the queries are not kept.
+func (s *Service) regenerateQueries(ctx context.Context, input Input) error {
+	input, err := s.resolveJSONTypes(input)
+	if err != nil {
+		return err
+	}
+
+	if input, err = s.resolveKeys(s.resolveReferences(input)); err != nil {
+		return err
+	}
+
+	if input, err = s.resolveChildren(input); err != nil {
+		return err
+	}
+
+	queriesFilePath := filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql")
+
+	if err = s.removeSQLQueries(queriesFilePath, dbMethodNames(input)); err != nil {
+		return err
+	}
+
+	if err = s.generateSQLMethods(ctx, input); err != nil {
+		return fmt.Errorf("failed generating sql methods: %w", err)
+	}
+
+	if err = s.trackFolder(filepath.Join(input.WorkspaceFolder, "internal", "dbx")); err != nil {
+		return err
+	}
+
+	if err = s.runCommand(input.WorkspaceFolder, "make", "sqlc-gen"); err != nil {
+		return fmt.Errorf("failed running make sqlc-gen: %w", err)
+	}
+
+	folderPath := filepath.Join(input.WorkspaceFolder, "internal", "service")
+
+	if err = s.removeMethodsFromFile(filepath.Join(folderPath, "database_iface.go"), dbMethodNames(input)); err != nil {
+		return err
+	}
+
+	if err = s.appendDBMethodsToIface(ctx, input); err != nil {
+		return fmt.Errorf("failed appending methods to database_iface.go: %w", err)
+	}
+
+	if err = s.runCommand(folderPath, "goimports", "-w", "database_iface.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
+
+// mergeResourceFiles renders the files a resource owns and merges them into
+// the project, keeping the pristine copies up to date. It returns the files
+// that have conflicts.
+func (s *Service) mergeResourceFiles(ctx context.Context, input Input) ([]string, error) {
+	rendered, err := s.renderResourceFiles(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicted := []string{}
+
+	for _, relativePath := range sortedKeys(rendered) {
+		conflicts, err := s.mergeRenderedFile(input.WorkspaceFolder, relativePath, rendered[relativePath])
+		if err != nil {
+			return nil, err
+		}
+
+		if conflicts {
+			conflicted = append(conflicted, relativePath)
+		}
+	}
+
+	return conflicted, nil
+}
+
+// mergeRenderedFile merges a rendered file into the project against its
+// pristine copy, and stores the rendered file as the new pristine copy. It
+// returns whether the merge has conflicts.
+//
+//nolint:cyclop
+func (s *Service) mergeRenderedFile(workspaceFolder string, relativePath string, rendered []byte) (bool, error) {
+	filePath := filepath.Join(workspaceFolder, filepath.FromSlash(relativePath))
+	pristinePath := pristineFilePath(workspaceFolder, relativePath)
+
+	var (
+		base []byte
+		err  error
+	)
+
+	if s.fileExists(pristinePath) {
+		if base, err = s.readFile(pristinePath); err != nil {
+			return false, err
+		}
+	}
+
+	current := []byte{}
+
+	if s.fileExists(filePath) {
+		if current, err = s.readFile(filePath); err != nil {
+			return false, err
+		}
+	} else if base != nil {
+		// the file was deleted by hand, so it stays deleted
+		return false, nil
+	}
+
+	merged, conflicts := mergeThreeWay(base, current, rendered)
+
+	if string(merged) != string(current) {
+		if err = s.ensureFolderExists(filepath.Dir(filePath)); err != nil {
+			return false, err
+		}
+
+		if err = s.writeFile(filePath, merged); err != nil {
+			return false, err
+		}
+
+		if conflicts == 0 && strings.HasSuffix(filePath, ".go") {
+			if err = s.runCommand(filepath.Dir(filePath), "goimports", "-w", filepath.Base(filePath)); err != nil {
+				return false, fmt.Errorf("failed running goimports: %w", err)
+			}
+		}
+	}
+
+	if err = s.ensureFolderExists(filepath.Dir(pristinePath)); err != nil {
+		return false, err
+	}
+
+	if err = s.writeFile(pristinePath, rendered); err != nil {
+		return false, err
+	}
+
+	return conflicts > 0, nil
+}
+
+// storePristineFiles keeps a copy of the template output for every file the
+// resource owns, to merge against when it is regenerated.
+func (s *Service) storePristineFiles(ctx context.Context, input Input) error {
+	rendered, err := s.renderResourceFiles(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	for _, relativePath := range sortedKeys(rendered) {
+		pristinePath := pristineFilePath(input.WorkspaceFolder, relativePath)
+
+		if err = s.ensureFolderExists(filepath.Dir(pristinePath)); err != nil {
+			return err
+		}
+
+		if err = s.writeFile(pristinePath, rendered[relativePath]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) removePristineFiles(workspaceFolder string, entry ManifestResource) error {
+	for _, relativePath := range entry.Files {
+		pristinePath := pristineFilePath(workspaceFolder, relativePath)
+
+		if !s.fileExists(pristinePath) {
+			continue
+		}
+
+		if err := s.removeFile(pristinePath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderResourceFiles runs the steps that produce the files a resource owns
+// against an empty folder in dry-run mode, and returns their output keyed by
+// path relative to the workspace. Go files are gofmt'd so that they line up
+// with the files goimports left in the project.
+func (s *Service) renderResourceFiles(ctx context.Context, input Input) (map[string][]byte, error) {
+	scratchFolder, err := os.MkdirTemp("", "oxgen-pristine-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch folder: %w", err)
+	}
+
+	defer os.RemoveAll(scratchFolder)
+
+	if input, err = s.resolveJSONTypes(input); err != nil {
+		return nil, err
+	}
+
+	if input, err = s.resolveChildren(input); err != nil {
+		return nil, err
+	}
+
+	scratch := New(WithDryRun(), WithTemplatesFolder(s.templatesFolder))
+	input.WorkspaceFolder = scratchFolder
+
+	steps := []func(context.Context, Input) error{
+		scratch.generateServiceMethods,
+		scratch.generatePresenter,
+		scratch.generateHandlerMethods,
+		scratch.generateFrontendModel,
+		scratch.writeFrontendSlice,
+		scratch.writeFrontendComponents,
+	}
+
+	for _, step := range steps {
+		if err = step(ctx, input); err != nil {
+			return nil, fmt.Errorf("failed rendering resource files: %w", err)
+		}
+	}
+
+	rendered := map[string][]byte{}
+
+	for path, content := range scratch.workspace.overlay {
+		relativePath, err := filepath.Rel(scratchFolder, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed rendering resource files: %w", err)
+		}
+
+		if strings.HasSuffix(path, ".go") {
+			if formatted, err := format.Source(content); err == nil {
+				content = formatted
+			}
+		}
+
+		rendered[filepath.ToSlash(relativePath)] = content
+	}
+
+	return rendered, nil
+}
+
+func pristineFilePath(workspaceFolder string, relativePath string) string {
+	return filepath.Join(workspaceFolder, ".oxgen", "pristine", filepath.FromSlash(relativePath))
+}
+
+func sortedKeys(files map[string][]byte) []string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
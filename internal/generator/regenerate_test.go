@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegenerateRendersQueries(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Post", "title:string")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	queriesPath := filepath.Join(workspaceFolder, "internal", "database", "queries.sql")
+	ifacePath := filepath.Join(workspaceFolder, "internal", "service", "database_iface.go")
+
+	// stand in for a template change since the resource was generated
+	service := New()
+
+	if err := service.removeSQLQueries(queriesPath, []string{"CountRecentPosts"}); err != nil {
+		t.Fatalf("failed to remove query: %v", err)
+	}
+
+	if err := service.removeMethodsFromFile(ifacePath, []string{"CountRecentPosts"}); err != nil {
+		t.Fatalf("failed to remove method: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(workspaceFolder, "commands.log")); err != nil {
+		t.Fatalf("failed to clear commands.log: %v", err)
+	}
+
+	generated := manifestEntry(t, workspaceFolder, "Post")
+
+	conflicted, err := New().Regenerate(context.Background(), testInput(t, workspaceFolder, "Post"))
+	if err != nil {
+		t.Fatalf("failed to regenerate: %v", err)
+	}
+
+	// regenerating renders the same files, at the same anchors
+	regenerated := manifestEntry(t, workspaceFolder, "Post")
+
+	if !slices.Equal(regenerated.Files, generated.Files) {
+		t.Errorf("expected the manifest files %v, got %v", generated.Files, regenerated.Files)
+	}
+
+	if !slices.Equal(regenerated.Anchors, generated.Anchors) {
+		t.Errorf("expected the manifest anchors %v, got %v", generated.Anchors, regenerated.Anchors)
+	}
+
+	if len(conflicted) > 0 {
+		t.Errorf("expected no conflicts, got %v", conflicted)
+	}
+
+	queries := readTestFile(t, queriesPath)
+
+	for _, query := range []string{"CreatePost", "FetchRecentPosts", "CountRecentPosts", "DeletePost"} {
+		if count := strings.Count(queries, "-- name: "+query+" "); count != 1 {
+			t.Errorf("expected %s once, got %d", query, count)
+		}
+	}
+
+	if count := strings.Count(readTestFile(t, ifacePath), "CountRecentPosts("); count != 1 {
+		t.Errorf("expected CountRecentPosts on DatabaseProvider once, got %d", count)
+	}
+
+	if commands := strings.Fields(readTestFile(t, filepath.Join(workspaceFolder, "commands.log"))); !strings.Contains(strings.Join(commands, " "), "sqlc") {
+		t.Errorf("expected sqlc to run, got %v", commands)
+	}
+}
+
+func TestRegenerateKeepsAnchorsOnce(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	if err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Post", "title:string")); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	anchors := func() int {
+		return len(manifestEntry(t, workspaceFolder, "Post").Anchors)
+	}
+
+	generated := anchors()
+
+	for run := 1; run <= 2; run++ {
+		if _, err := New().Regenerate(context.Background(), testInput(t, workspaceFolder, "Post")); err != nil {
+			t.Fatalf("failed to regenerate: %v", err)
+		}
+
+		if count := anchors(); count != generated {
+			t.Errorf("expected %d anchors after regenerating %d times, got %d", generated, run, count)
+		}
+	}
+}
@@ -0,0 +1,511 @@
+//nolint:lll,revive
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var (
+	ErrInvalidRelation = errors.New("invalid relation")
+	ErrRelationExists  = errors.New("relation already exists")
+)
+
+type RelationKind string
+
+const RelationManyToMany RelationKind = "many_to_many"
+
+// RelationInput names two resources, both already in the project, to relate
+// to each other.
+type RelationInput struct {
+	WorkspaceFolder string
+	Left            TemplateName
+	Right           TemplateName
+	LeftID          IDStrategy
+	RightID         IDStrategy
+}
+
+// JoinTable is the table that holds the pairs of a many-to-many relation.
+func (r RelationInput) JoinTable() string {
+	return r.Left.UnderscorePlural() + "_" + r.Right.UnderscorePlural()
+}
+
+// relationSide is one direction of a relation: the methods that list, attach
+// and detach Other records for an Owner, which live in the Owner's service.
+type relationSide struct {
+	Service         TemplateName
+	Owner           TemplateName
+	Other           TemplateName
+	JoinTable       string
+	OtherSoftDelete bool
+	OwnerID         IDStrategy
+	OtherID         IDStrategy
+}
+
+func (s relationSide) MemberWrapper() string {
+	return memberWrapper(s.OwnerID)
+}
+
+// ManyToMany relates two existing resources through a join table, with
+// methods to attach, detach and list records on both sides.
+func (s *Service) ManyToMany(ctx context.Context, input RelationInput) error {
+	for _, name := range []TemplateName{input.Left, input.Right} {
+		if err := ensureValidResourceName(name.String()); err != nil {
+			return err
+		}
+	}
+
+	if input.Left.CamelcaseSingular() == input.Right.CamelcaseSingular() {
+		return fmt.Errorf("%s cannot be related to itself: %w", input.Left, ErrInvalidRelation)
+	}
+
+	s.beginRun()
+
+	if err := s.ensureNewRelation(input); err != nil {
+		return err
+	}
+
+	sides, err := s.relationSides(input)
+	if err != nil {
+		return err
+	}
+
+	input.LeftID = sides[0].OwnerID
+	input.RightID = sides[0].OtherID
+
+	if err = s.manyToMany(ctx, input, sides); err != nil {
+		if s.dryRun {
+			return err
+		}
+
+		return s.rollback(Input{WorkspaceFolder: input.WorkspaceFolder}, err)
+	}
+
+	return nil
+}
+
+func (s *Service) ensureNewRelation(input RelationInput) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	if _, found := manifest.FindRelation(RelationManyToMany, input.Left.String(), input.Right.String()); found {
+		return fmt.Errorf("%s and %s: %w", input.Left, input.Right, ErrRelationExists)
+	}
+
+	schemaPath := filepath.Join(input.WorkspaceFolder, "internal", "database", "schema.sql")
+	if !s.fileExists(schemaPath) {
+		return nil
+	}
+
+	content, err := s.readFile(schemaPath)
+	if err != nil {
+		return err
+	}
+
+	if tableDefined(content, input.JoinTable()) {
+		return fmt.Errorf("table %s: %w", input.JoinTable(), ErrRelationExists)
+	}
+
+	return nil
+}
+
+// relationSides looks up the services of both resources, and returns the
+// relation as seen from each of them.
+func (s *Service) relationSides(input RelationInput) ([]relationSide, error) {
+	leftService, err := s.resourceService(input.WorkspaceFolder, input.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	rightService, err := s.resourceService(input.WorkspaceFolder, input.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	return []relationSide{
+		{Service: leftService, Owner: input.Left, Other: input.Right, JoinTable: input.JoinTable(), OtherSoftDelete: manifest.softDeleted(input.Right), OwnerID: manifest.key(input.Left), OtherID: manifest.key(input.Right)},
+		{Service: rightService, Owner: input.Right, Other: input.Left, JoinTable: input.JoinTable(), OtherSoftDelete: manifest.softDeleted(input.Left), OwnerID: manifest.key(input.Right), OtherID: manifest.key(input.Left)},
+	}, nil
+}
+
+// resourceService finds the service a generated resource belongs to, from the
+// manifest or, for resources generated before it, from its create method.
+func (s *Service) resourceService(workspaceFolder string, resource TemplateName) (TemplateName, error) {
+	presenterPath := filepath.Join(workspaceFolder, "internal", "handler", "api", "presenter", resource.UnderscoreSingular()+".go")
+	if !s.fileExists(presenterPath) {
+		return "", fmt.Errorf("%s: %w", resource, ErrResourceNotFound)
+	}
+
+	manifest, err := s.LoadManifest(workspaceFolder)
+	if err != nil {
+		return "", err
+	}
+
+	if entry, found := manifest.Find(resource.String()); found {
+		return TemplateName(entry.Service), nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(workspaceFolder, "internal", "service", "*", "create_"+resource.UnderscoreSingular()+".go"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list services: %w", err)
+	}
+
+	if len(paths) != 1 {
+		return "", fmt.Errorf("%s: %w", resource, ErrServiceRequired)
+	}
+
+	return TemplateName(filepath.Base(filepath.Dir(paths[0]))), nil
+}
+
+//nolint:funlen,cyclop
+func (s *Service) manyToMany(ctx context.Context, input RelationInput, sides []relationSide) error {
+	// migration
+	if err := s.generateJoinTableMigration(ctx, input); err != nil {
+		return fmt.Errorf("failed generating join table migration: %w", err)
+	}
+
+	// run migration, dump schema and generate models
+	if err := s.runCommand(input.WorkspaceFolder, "make", "db-migrate"); err != nil {
+		return fmt.Errorf("failed running make db-migrate: %w", err)
+	}
+
+	s.workspace.migrations++
+
+	if err := s.trackFile(filepath.Join(input.WorkspaceFolder, "internal", "database", "schema.sql")); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "db-schema-dump"); err != nil {
+		return fmt.Errorf("failed running make db-schema-dump: %w", err)
+	}
+
+	queriesFilePath := filepath.Join(input.WorkspaceFolder, "internal", "database", "queries.sql")
+
+	for _, side := range sides {
+		if err := s.appendTemplateToFile(ctx, queriesFilePath, "many_to_many_sql_methods", side); err != nil {
+			return fmt.Errorf("failed to generate %s %s SQL methods: %w", side.Owner, side.Other.LowerCamelcasePlural(), err)
+		}
+	}
+
+	// run sqlc gen
+	if err := s.trackFolder(filepath.Join(input.WorkspaceFolder, "internal", "dbx")); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "make", "sqlc-gen"); err != nil {
+		return fmt.Errorf("failed running make sqlc-gen: %w", err)
+	}
+
+	serviceFolder := filepath.Join(input.WorkspaceFolder, "internal", "service")
+
+	for _, side := range sides {
+		if err := s.injectIntoInterface(filepath.Join(serviceFolder, "database_iface.go"), "DatabaseProvider", "many_to_many_db_methods_iface", side); err != nil {
+			return err
+		}
+	}
+
+	if err := s.runCommand(serviceFolder, "goimports", "-w", "database_iface.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	for _, side := range sides {
+		if err := s.generateRelationSide(ctx, input, side); err != nil {
+			return err
+		}
+	}
+
+	if err := s.recordRelation(input); err != nil {
+		return fmt.Errorf("failed updating manifest: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) generateJoinTableMigration(_ context.Context, input RelationInput) error {
+	if err := s.ensureFolderExists(filepath.Join(input.WorkspaceFolder, "migrations")); err != nil {
+		return err
+	}
+
+	timestamp, err := s.nextMigrationVersion(filepath.Join(input.WorkspaceFolder, "migrations"))
+	if err != nil {
+		return err
+	}
+
+	for _, direction := range []string{"up", "down"} {
+		sql, err := s.renderTemplate("join_table_"+direction, input)
+		if err != nil {
+			return fmt.Errorf("failed to render %s template: %w", direction, err)
+		}
+
+		if err = s.writeFile(
+			filepath.Join(
+				input.WorkspaceFolder,
+				"migrations",
+				fmt.Sprintf("%s_create_%s_table.%s.sql", timestamp, input.JoinTable(), direction),
+			),
+			sql,
+		); err != nil {
+			return fmt.Errorf("failed to create %s file: %w", direction, err)
+		}
+	}
+
+	return nil
+}
+
+// generateRelationSide adds the service methods, handlers, routes and
+// frontend endpoints for one side of a relation.
+//
+//nolint:funlen
+func (s *Service) generateRelationSide(ctx context.Context, input RelationInput, side relationSide) error {
+	ownerPlural := side.Owner.UnderscorePlural()
+	ownerSingular := side.Owner.UnderscoreSingular()
+	otherPlural := side.Other.UnderscorePlural()
+	otherSingular := side.Other.UnderscoreSingular()
+
+	if err := s.writeServiceMethodFiles(
+		ctx,
+		filepath.Join(input.WorkspaceFolder, "internal", "service", side.Service.String()),
+		map[string]templateDetails{
+			"attachServiceMethod":       {filename: "attach_" + otherSingular + "_to_" + ownerSingular + ".go", template: "attach_service_method", input: side},
+			"detachServiceMethod":       {filename: "detach_" + otherSingular + "_from_" + ownerSingular + ".go", template: "detach_service_method", input: side},
+			"fetchRelatedServiceMethod": {filename: "fetch_" + otherPlural + "_for_" + ownerSingular + ".go", template: "fetch_related_service_method", input: side},
+		},
+	); err != nil {
+		return fmt.Errorf("failed adding service methods: %w", err)
+	}
+
+	internalFolder := filepath.Join(input.WorkspaceFolder, "internal")
+	ifaceFilename := side.Service.String() + "_service_iface.go"
+
+	if err := s.injectIntoInterface(filepath.Join(internalFolder, ifaceFilename), side.Service.Capitalize()+"Service", "many_to_many_service_methods_iface", side); err != nil {
+		return fmt.Errorf("failed adding service methods to interface: %w", err)
+	}
+
+	if err := s.runCommand(internalFolder, "goimports", "-w", ifaceFilename); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	if err := s.writeHandlerMethodFiles(
+		ctx,
+		filepath.Join(input.WorkspaceFolder, "internal", "handler", "api"),
+		map[string]templateDetails{
+			"attachHandlerMethod":       {filename: ownerPlural + "_attach_" + otherSingular + ".go", template: "attach_handler_method", input: side},
+			"detachHandlerMethod":       {filename: ownerPlural + "_detach_" + otherSingular + ".go", template: "detach_handler_method", input: side},
+			"fetchRelatedHandlerMethod": {filename: ownerPlural + "_fetch_" + otherPlural + ".go", template: "fetch_related_handler_method", input: side},
+		},
+	); err != nil {
+		return fmt.Errorf("failed adding handler methods: %w", err)
+	}
+
+	routeFolder := filepath.Join(input.WorkspaceFolder, "internal", "route")
+
+	if err := s.injectIntoFunction(filepath.Join(routeFolder, "api.go"), "registerAPIRoutes", "many_to_many_route_methods", side); err != nil {
+		return fmt.Errorf("failed to generate relation routes: %w", err)
+	}
+
+	if err := s.runCommand(routeFolder, "goimports", "-w", "api.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return s.injectRelationFrontend(input, side)
+}
+
+func (s *Service) injectRelationFrontend(input RelationInput, side relationSide) error {
+	slicePath := filepath.Join(input.WorkspaceFolder, "frontend", "src", "slices", side.Owner.CamelcaseSingular()+".ts")
+
+	injections := []fileInjection{
+		{slicePath, "import { " + side.Owner.CamelcaseSingular() + " } from '../models/" + side.Owner.CamelcaseSingular() + "';", true, "many_to_many_frontend_imports"},
+		{slicePath, "export const api = createApi({", false, "many_to_many_frontend_requests"},
+		{slicePath, "endpoints: builder => ({", true, "many_to_many_frontend_endpoints"},
+		{slicePath, "useDestroyMutation", false, "many_to_many_frontend_hooks"},
+	}
+
+	for _, injection := range injections {
+		var err error
+
+		if injection.below {
+			err = s.injectTemplateBelowLine(injection.filePath, injection.anchorLine, injection.name, side)
+		} else {
+			err = s.injectTemplateAboveLine(injection.filePath, injection.anchorLine, injection.name, side)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to extend %s: %w", injection.filePath, err)
+		}
+	}
+
+	return nil
+}
+
+// recordRelation stores what the current run generated for the relation in
+// the manifest.
+func (s *Service) recordRelation(input RelationInput) error {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	entry := ManifestRelation{
+		Kind:  RelationManyToMany,
+		Left:  input.Left.String(),
+		Right: input.Right.String(),
+	}
+
+	s.addRunToEntry(input.WorkspaceFolder, &entry.ManifestChanges)
+	manifest.Relations = append(manifest.Relations, entry)
+
+	return s.saveManifest(input.WorkspaceFolder, manifest)
+}
+
+// resourceRelations are the relations a resource is part of, as the manifest
+// records them, with the key strategies of both sides.
+func (s *Service) resourceRelations(input Input) ([]RelationInput, error) {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return nil, err
+	}
+
+	relations := []RelationInput{}
+
+	for _, entry := range manifest.Relations {
+		if entry.Left != input.Resource.String() && entry.Right != input.Resource.String() {
+			continue
+		}
+
+		relations = append(relations, RelationInput{
+			WorkspaceFolder: input.WorkspaceFolder,
+			Left:            TemplateName(entry.Left),
+			Right:           TemplateName(entry.Right),
+			LeftID:          manifest.key(TemplateName(entry.Left)),
+			RightID:         manifest.key(TemplateName(entry.Right)),
+		})
+	}
+
+	return relations, nil
+}
+
+// removeRelation removes what ManyToMany generated for both sides of a
+// relation, except for its join table, which the caller's migration drops.
+func (s *Service) removeRelation(input RelationInput) error {
+	sides, err := s.relationSides(input)
+	if err != nil {
+		return err
+	}
+
+	for _, side := range sides {
+		if err = s.removeRelationSide(input, side); err != nil {
+			return fmt.Errorf("failed removing %s %s: %w", side.Owner, side.Other.LowerCamelcasePlural(), err)
+		}
+	}
+
+	return nil
+}
+
+//nolint:funlen
+func (s *Service) removeRelationSide(input RelationInput, side relationSide) error {
+	internalFolder := filepath.Join(input.WorkspaceFolder, "internal")
+	dbMethods := relationDBMethodNames(side)
+
+	if err := s.removeSQLQueries(filepath.Join(internalFolder, "database", "queries.sql"), dbMethods); err != nil {
+		return err
+	}
+
+	if err := s.removeMethodsFromFile(filepath.Join(internalFolder, "service", "database_iface.go"), dbMethods); err != nil {
+		return err
+	}
+
+	if err := s.removeMethodsFromFile(
+		filepath.Join(internalFolder, side.Service.String()+"_service_iface.go"),
+		relationServiceMethodNames(side),
+	); err != nil {
+		return err
+	}
+
+	ownerPlural := side.Owner.UnderscorePlural()
+	ownerSingular := side.Owner.UnderscoreSingular()
+	otherPlural := side.Other.UnderscorePlural()
+	otherSingular := side.Other.UnderscoreSingular()
+	serviceFolder := filepath.Join(internalFolder, "service", side.Service.String())
+	handlerFolder := filepath.Join(internalFolder, "handler", "api")
+
+	for _, path := range []string{
+		filepath.Join(serviceFolder, "attach_"+otherSingular+"_to_"+ownerSingular+".go"),
+		filepath.Join(serviceFolder, "detach_"+otherSingular+"_from_"+ownerSingular+".go"),
+		filepath.Join(serviceFolder, "fetch_"+otherPlural+"_for_"+ownerSingular+".go"),
+		filepath.Join(handlerFolder, ownerPlural+"_attach_"+otherSingular+".go"),
+		filepath.Join(handlerFolder, ownerPlural+"_detach_"+otherSingular+".go"),
+		filepath.Join(handlerFolder, ownerPlural+"_fetch_"+otherPlural+".go"),
+	} {
+		if err := s.removeFile(path); err != nil {
+			return err
+		}
+	}
+
+	routeFolder := filepath.Join(internalFolder, "route")
+	handlers := lo.Map(relationHandlerNames(side), func(name string, _ int) string { return "api." + name + "(services)" })
+
+	if err := s.removeLinesFromFile(filepath.Join(routeFolder, "api.go"), func(line string) bool {
+		return lo.SomeBy(handlers, func(handler string) bool { return strings.Contains(line, handler) })
+	}); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(routeFolder, "goimports", "-w", "api.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	slicePath := filepath.Join(input.WorkspaceFolder, "frontend", "src", "slices", side.Owner.CamelcaseSingular()+".ts")
+	if !s.fileExists(slicePath) {
+		return nil
+	}
+
+	for _, templateName := range []string{
+		"many_to_many_frontend_imports",
+		"many_to_many_frontend_requests",
+		"many_to_many_frontend_endpoints",
+		"many_to_many_frontend_hooks",
+	} {
+		if err := s.removeInjectedTemplate(slicePath, templateName, side); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func relationDBMethodNames(side relationSide) []string {
+	return []string{
+		"Attach" + side.Other.CamelcaseSingular() + "To" + side.Owner.CamelcaseSingular(),
+		"Detach" + side.Other.CamelcaseSingular() + "From" + side.Owner.CamelcaseSingular(),
+		"Fetch" + side.Other.CamelcasePlural() + "For" + side.Owner.CamelcaseSingular(),
+		"Count" + side.Other.CamelcasePlural() + "For" + side.Owner.CamelcaseSingular(),
+	}
+}
+
+func relationServiceMethodNames(side relationSide) []string {
+	return []string{
+		"Attach" + side.Other.CamelcaseSingular() + "To" + side.Owner.CamelcaseSingular(),
+		"Detach" + side.Other.CamelcaseSingular() + "From" + side.Owner.CamelcaseSingular(),
+		"Fetch" + side.Other.CamelcasePlural() + "For" + side.Owner.CamelcaseSingular(),
+	}
+}
+
+func relationHandlerNames(side relationSide) []string {
+	return []string{
+		side.Owner.CamelcasePlural() + "Fetch" + side.Other.CamelcasePlural(),
+		side.Owner.CamelcasePlural() + "Attach" + side.Other.CamelcaseSingular(),
+		side.Owner.CamelcasePlural() + "Detach" + side.Other.CamelcaseSingular(),
+	}
+}
@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrMakeTargetMissing = errors.New("make target missing")
+
+// RollbackError is returned by Generate when a step failed and the workspace
+// was restored to its state from before the run.
+type RollbackError struct {
+	Err      error
+	Restored []string
+	Removed  []string
+	Failures []error
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%v (rolled back %d restored, %d removed)", e.Err, len(e.Restored), len(e.Removed))
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// Summary describes every file that was restored or removed, and anything
+// that could not be undone.
+func (e *RollbackError) Summary() string {
+	lines := []string{"Generation failed, rolled back changes:"}
+
+	for _, path := range e.Restored {
+		lines = append(lines, "  restored "+path)
+	}
+
+	for _, path := range e.Removed {
+		lines = append(lines, "  removed  "+path)
+	}
+
+	for _, err := range e.Failures {
+		lines = append(lines, "  failed   "+err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (s *Service) beginRun() {
+	if !s.dryRun {
+		s.workspace = newWorkspace()
+	}
+
+	s.workspace.run = runRecord{}
+}
+
+//nolint:cyclop,funlen
+func (s *Service) rollback(input Input, cause error) error {
+	rollbackErr := &RollbackError{Err: cause}
+
+	// the migration files are still present, so the database can be stepped
+	// back before they go away. db-rollback undoes one migration at a time.
+	if s.workspace.migrations > 0 {
+		if err := ensureMakeTarget(input.WorkspaceFolder, "db-rollback"); err != nil {
+			rollbackErr.Failures = append(rollbackErr.Failures, fmt.Errorf("rolled back none of the %d applied migrations: %w", s.workspace.migrations, err))
+		} else {
+			for range s.workspace.migrations {
+				if err := s.runCommand(input.WorkspaceFolder, "make", "db-rollback"); err != nil {
+					rollbackErr.Failures = append(rollbackErr.Failures, err)
+
+					break
+				}
+			}
+		}
+	}
+
+	for i := len(s.workspace.order) - 1; i >= 0; i-- {
+		path := s.workspace.order[i]
+		original := s.workspace.originals[path]
+
+		if original.existed {
+			//nolint:gomnd
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				rollbackErr.Failures = append(rollbackErr.Failures, fmt.Errorf("failed to restore %s: %w", path, err))
+
+				continue
+			}
+
+			//nolint:gomnd,gosec
+			if err := os.WriteFile(path, original.content, 0o644); err != nil {
+				rollbackErr.Failures = append(rollbackErr.Failures, fmt.Errorf("failed to restore %s: %w", path, err))
+
+				continue
+			}
+
+			rollbackErr.Restored = append(rollbackErr.Restored, path)
+
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				rollbackErr.Failures = append(rollbackErr.Failures, fmt.Errorf("failed to remove %s: %w", path, err))
+			}
+
+			continue
+		}
+
+		rollbackErr.Removed = append(rollbackErr.Removed, path)
+	}
+
+	trackedFolders := make([]string, 0, len(s.workspace.trackedFolders))
+	for folder := range s.workspace.trackedFolders {
+		trackedFolders = append(trackedFolders, folder)
+	}
+
+	sort.Strings(trackedFolders)
+
+	for _, folder := range trackedFolders {
+		entries, err := os.ReadDir(folder)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			filePath := filepath.Join(folder, entry.Name())
+			if entry.IsDir() || s.workspace.trackedFolders[folder][filePath] {
+				continue
+			}
+
+			if err = os.Remove(filePath); err != nil {
+				rollbackErr.Failures = append(rollbackErr.Failures, fmt.Errorf("failed to remove %s: %w", filePath, err))
+
+				continue
+			}
+
+			rollbackErr.Removed = append(rollbackErr.Removed, filePath)
+		}
+	}
+
+	for i := len(s.workspace.createdFolders) - 1; i >= 0; i-- {
+		folder := s.workspace.createdFolders[i]
+
+		if err := os.RemoveAll(folder); err != nil {
+			rollbackErr.Failures = append(rollbackErr.Failures, fmt.Errorf("failed to remove %s: %w", folder, err))
+
+			continue
+		}
+
+		rollbackErr.Removed = append(rollbackErr.Removed, folder+string(filepath.Separator))
+	}
+
+	return rollbackErr
+}
+
+// ensureMakeTarget checks that the project's Makefile has the target. Webapps
+// scaffolded before db-rollback was added to the template lack it.
+func ensureMakeTarget(workspaceFolder string, target string) error {
+	makefilePath := filepath.Join(workspaceFolder, "Makefile")
+
+	content, err := os.ReadFile(makefilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", makefilePath, err)
+	}
+
+	if !regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(target) + `:`).Match(content) {
+		return fmt.Errorf("%s has no %s target, add one running migrate ... down 1: %w", makefilePath, target, ErrMakeTargetMissing)
+	}
+
+	return nil
+}
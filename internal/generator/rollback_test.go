@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestForcedGenerateRollsBackEveryMigration(t *testing.T) {
+	// sqlc-gen fails the second time it runs in the forced run, after both
+	// the drop and the create migration were applied
+	makefile := strings.Replace(testMakefile, "\techo sqlc >> commands.log\n", "\techo sqlc >> commands.log\n\ttest $$(grep -c sqlc commands.log) -lt 2\n", 1)
+	workspaceFolder := newTestWorkspace(t, makefile)
+	input := testInput(t, workspaceFolder, "Post", "title:string")
+
+	if err := New().Generate(context.Background(), input); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	migrationsBefore := migrationFiles(t, workspaceFolder)
+
+	if err := os.Remove(filepath.Join(workspaceFolder, "commands.log")); err != nil {
+		t.Fatalf("failed to clear commands.log: %v", err)
+	}
+
+	err := New(WithForce()).Generate(context.Background(), input)
+
+	var rollbackErr *RollbackError
+	if !errors.As(err, &rollbackErr) {
+		t.Fatalf("expected a rollback error, got %v", err)
+	}
+
+	commands := strings.Fields(readTestFile(t, filepath.Join(workspaceFolder, "commands.log")))
+
+	if migrations, rollbacks := countOf(commands, "migrate"), countOf(commands, "rollback"); migrations != 2 || rollbacks != 2 {
+		t.Errorf("expected 2 migrations and 2 rollbacks, got %d and %d: %v", migrations, rollbacks, commands)
+	}
+
+	if migrationsAfter := migrationFiles(t, workspaceFolder); strings.Join(migrationsAfter, " ") != strings.Join(migrationsBefore, " ") {
+		t.Errorf("expected migrations %v, got %v", migrationsBefore, migrationsAfter)
+	}
+}
+
+func TestRollbackWithoutRollbackTarget(t *testing.T) {
+	// webapps scaffolded before db-rollback was added lack the target
+	makefile := strings.Replace(testMakefile, "db-rollback:\n\techo rollback >> commands.log\n", "", 1)
+	makefile = strings.Replace(makefile, "\techo sqlc >> commands.log\n", "\techo sqlc >> commands.log\n\tfalse\n", 1)
+	workspaceFolder := newTestWorkspace(t, makefile)
+
+	err := New().Generate(context.Background(), testInput(t, workspaceFolder, "Post", "title:string"))
+
+	var rollbackErr *RollbackError
+	if !errors.As(err, &rollbackErr) {
+		t.Fatalf("expected a rollback error, got %v", err)
+	}
+
+	if len(rollbackErr.Failures) != 1 || !errors.Is(rollbackErr.Failures[0], ErrMakeTargetMissing) {
+		t.Errorf("expected the missing target to be reported, got %v", rollbackErr.Failures)
+	}
+
+	if !strings.Contains(rollbackErr.Summary(), "rolled back none of the 1 applied migrations") {
+		t.Errorf("expected the summary to mention the applied migration, got:\n%s", rollbackErr.Summary())
+	}
+
+	if migrations, _ := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*.sql")); len(migrations) != 0 {
+		t.Errorf("expected the migration files to be removed, got %v", migrations)
+	}
+}
+
+func countOf(values []string, value string) int {
+	count := 0
+
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+
+	return count
+}
+
+func migrationFiles(t *testing.T, workspaceFolder string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(filepath.Join(workspaceFolder, "migrations"))
+	if err != nil {
+		t.Fatalf("failed to list migrations: %v", err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+var ErrInvalidSearch = errors.New("invalid search")
+
+// searchVectorColumn is the generated column full-text searched resources
+// keep their search document in.
+const searchVectorColumn = "search_vector"
+
+// searchWeights rank matches in the first full-text fields above those in
+// the later ones.
+var searchWeights = []string{"A", "B", "C", "D"} //nolint:gochecknoglobals
+
+// ParseSearch reads a --search value. fts:title,body searches the title and
+// body fields with Postgres full-text search; an empty value searches
+// nothing.
+func ParseSearch(value string) ([]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	fields, found := strings.CutPrefix(value, "fts:")
+	if !found || fields == "" {
+		return nil, fmt.Errorf("%q: %w", value, ErrInvalidSearch)
+	}
+
+	return strings.Split(fields, ","), nil
+}
+
+// FullTextSearch resources are searched through a tsvector of their
+// FullTextFields, and ILIKE searched on their SearchField otherwise.
+func (i Input) FullTextSearch() bool {
+	return len(i.FullTextFields) > 0
+}
+
+// RankedSearch is true for cursor paginated full-text searches, whose pages
+// are in rank order and come with the rank of each of their items.
+func (i Input) RankedSearch() bool {
+	return i.FullTextSearch() && i.CursorPagination()
+}
+
+// FullTextSearchFields are the fields of FullTextFields, in their order.
+func (i Input) FullTextSearchFields() []InputField {
+	return lo.Filter(i.Fields, func(f InputField, _ int) bool { return lo.Contains(i.FullTextFields, f.Name.String()) })
+}
+
+// ensureFullTextFields checks that the full-text fields are string fields of
+// the resource.
+func (i Input) ensureFullTextFields() error {
+	for _, name := range i.FullTextFields {
+		field, found := lo.Find(i.Fields, func(f InputField) bool { return f.Name.String() == name })
+		if !found || field.Type != FieldTypeString || field.Array {
+			return fmt.Errorf("%s: %s is not a string field: %w", i.Resource, name, ErrInvalidSearch)
+		}
+	}
+
+	return nil
+}
+
+// SearchVectorSQL declares the generated tsvector column of a full-text
+// searched table, weighting its fields in the order they were given.
+func (i Input) SearchVectorSQL() string {
+	documents := []string{}
+
+	for n, name := range i.FullTextFields {
+		weight := searchWeights[min(n, len(searchWeights)-1)]
+		documents = append(documents, fmt.Sprintf("setweight(to_tsvector('english', coalesce(%s, '')), '%s')", name, weight))
+	}
+
+	return searchVectorColumn + " tsvector GENERATED ALWAYS AS (" + strings.Join(documents, " || ") + ") STORED"
+}
+
+func searchQuerySQL() string {
+	return "websearch_to_tsquery('english', @query::text)"
+}
+
+// SearchPredicateSQL is the WHERE condition of the search queries. Both
+// kinds of search match every row for an empty query.
+func (i Input) SearchPredicateSQL() string {
+	if !i.FullTextSearch() {
+		return "t." + i.SearchField + " ILIKE '%' || @query::text || '%'"
+	}
+
+	return "(@query::text = '' OR t." + searchVectorColumn + " @@ " + searchQuerySQL() + ")"
+}
+
+// SearchOrderSQL is the default order of offset paginated search results:
+// by the searched field, or by rank for full-text search.
+func (i Input) SearchOrderSQL() string {
+	if !i.FullTextSearch() {
+		return "t." + i.SearchField + " ASC"
+	}
+
+	return i.SearchRankSQL() + " DESC"
+}
+
+// SearchRankSQL is how well a row matches a full-text search query. Cursor
+// paginated full-text searches are ordered by it, the id breaking ties, and
+// their cursors hold the rank of the row they are at.
+func (i Input) SearchRankSQL() string {
+	return "ts_rank(t." + searchVectorColumn + ", " + searchQuerySQL() + ")"
+}
+
+// HighlightColumnsSQL select the full-text fields with the words matching the
+// search query marked, for the Highlight query.
+func (i Input) HighlightColumnsSQL() string {
+	columns := []string{}
+
+	for _, name := range i.FullTextFields {
+		columns = append(columns, fmt.Sprintf("ts_headline('english', coalesce(t.%s, ''), %s, 'StartSel=<mark>, StopSel=</mark>')::text AS %s", name, searchQuerySQL(), name))
+	}
+
+	return strings.Join(columns, ",\n    ")
+}
+
+// searchVectorOverrides point sqlc at a string for the search vector, which
+// it has no Go type for.
+func (i Input) searchVectorOverrides() []sqlcOverride {
+	if !i.FullTextSearch() {
+		return nil
+	}
+
+	return []sqlcOverride{{
+		Column: i.Resource.UnderscorePlural() + "." + searchVectorColumn,
+		GoType: sqlcGoType{Type: "string"},
+	}}
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureFullTextFields(t *testing.T) {
+	tests := []struct {
+		name       string
+		fields     []string
+		pagination Pagination
+		valid      bool
+	}{
+		{"string field", []string{"title"}, PaginationOffset, true},
+		{"unknown field", []string{"body"}, PaginationOffset, false},
+		{"non-string field", []string{"views"}, PaginationOffset, false},
+		{"cursor pagination", []string{"title"}, PaginationCursor, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := testInput(t, "", "Post", "title:string", "views:int")
+			input.FullTextFields = test.fields
+			input.Pagination = test.pagination
+
+			err := input.ensureFullTextFields()
+			if test.valid && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+
+			if !test.valid && !errors.Is(err, ErrInvalidSearch) {
+				t.Errorf("expected %v, got %v", ErrInvalidSearch, err)
+			}
+		})
+	}
+}
+
+func TestCursorPaginatedFullTextSearch(t *testing.T) {
+	workspaceFolder := newTestWorkspace(t, testMakefile)
+
+	tag := testInput(t, workspaceFolder, "Tag", "name:string")
+	tag.Pagination = PaginationCursor
+
+	if err := New().Generate(context.Background(), tag); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	// stand in for a cursor added before ranked searches were
+	cursorPath := filepath.Join(workspaceFolder, "internal", "service", "cursor.go")
+	cursorType := readTestFile(t, cursorPath)
+	cursorType = strings.Replace(cursorType, "\tRank      float32   `json:\"r,omitempty\"`\n", "", 1)
+
+	if err := os.WriteFile(cursorPath, []byte(cursorType), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", cursorPath, err)
+	}
+
+	post := testInput(t, workspaceFolder, "Post", "title:string", "body:string")
+	post.FullTextFields = []string{"title", "body"}
+	post.HasSearch = true
+	post.Pagination = PaginationCursor
+
+	if err := New().Generate(context.Background(), post); err != nil {
+		t.Fatalf("failed to generate: %v", err)
+	}
+
+	expected := map[string][]string{
+		filepath.Join("internal", "database", "queries.sql"): {
+			"SELECT sqlc.embed(t), ts_rank(t.search_vector, websearch_to_tsquery('english', @query::text))::real AS rank",
+			"(ts_rank(t.search_vector, websearch_to_tsquery('english', @query::text)), t.id) < (@cursor_rank::real, @cursor_id::uuid)",
+			"ORDER BY rank DESC, t.id DESC",
+			"ORDER BY rank ASC, t.id ASC",
+		},
+		filepath.Join("internal", "service", "database_iface.go"): {"([]dbx.SearchPostsRow, error)", "([]dbx.SearchPostsBeforeRow, error)"},
+		filepath.Join("internal", "service", "cursor.go"):         {"Rank float32 `json:\"r,omitempty\"`"},
+		filepath.Join("internal", "service", "blog", "search_posts.go"): {
+			"([]dbx.Post, []float32, bool, error)",
+			"CursorRank: cursor.Rank,",
+			"ranks = append(ranks, row.Rank)",
+		},
+		filepath.Join("internal", "handler", "api", "posts_search.go"): {"items, ranks, more, err :=", "service.Cursor[uuid.UUID]{Rank: ranks[i], ID: items[i].ID}"},
+	}
+
+	for relativePath, snippets := range expected {
+		content := readTestFile(t, filepath.Join(workspaceFolder, relativePath))
+
+		for _, snippet := range snippets {
+			if !strings.Contains(strings.Join(strings.Fields(content), " "), strings.Join(strings.Fields(snippet), " ")) {
+				t.Errorf("expected %s to contain %q, got:\n%s", relativePath, snippet, content)
+			}
+		}
+	}
+}
@@ -1,7 +1,51 @@
 package generator
 
-func New() *Service {
-	return &Service{}
+import "text/template"
+
+func New(options ...Option) *Service {
+	s := &Service{
+		workspace: newWorkspace(),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	return s
+}
+
+type Option func(s *Service)
+
+// WithDryRun makes the service render every change into an in-memory overlay
+// of the workspace instead of writing to disk, and record external commands
+// instead of running them.
+func WithDryRun() Option {
+	return func(s *Service) {
+		s.dryRun = true
+	}
 }
 
-type Service struct{}
+// WithTemplatesFolder makes templates in the folder replace the built-in
+// templates of the same name.
+func WithTemplatesFolder(path string) Option {
+	return func(s *Service) {
+		s.templatesFolder = path
+	}
+}
+
+// WithForce makes Generate replace a resource that is already in the project
+// instead of refusing to generate it. The existing resource is destroyed
+// first, which drops its table.
+func WithForce() Option {
+	return func(s *Service) {
+		s.force = true
+	}
+}
+
+type Service struct {
+	dryRun          bool
+	force           bool
+	templatesFolder string
+	templates       *template.Template
+	workspace       *workspace
+}
@@ -0,0 +1,347 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidSpec      = errors.New("invalid spec")
+	ErrCyclicSpec       = errors.New("resources in spec depend on each other")
+	ErrUnknownSpecTable = errors.New("referenced table not found")
+)
+
+// Spec describes a set of resources to generate. It is read from a YAML or
+// JSON file, and unlike the positional field strings it can hold defaults and
+// enum values containing ':' or ','.
+type Spec struct {
+	// ID is the key strategy of the resources that don't pick their own.
+	ID       string        `yaml:"id"`
+	Services []SpecService `yaml:"services"`
+}
+
+type SpecService struct {
+	Name      string         `yaml:"name"`
+	Resources []SpecResource `yaml:"resources"`
+}
+
+type SpecResource struct {
+	Name        string      `yaml:"name"`
+	Parent      string      `yaml:"parent"`
+	Nested      bool        `yaml:"nested"`
+	SoftDelete  bool        `yaml:"soft_delete"`
+	ID          string      `yaml:"id"`
+	Pagination  string      `yaml:"pagination"`
+	SearchField string      `yaml:"search_field"`
+	Search      string      `yaml:"search"`
+	Bulk        bool        `yaml:"bulk"`
+	UpsertKey   string      `yaml:"upsert_key"`
+	Fields      []SpecField `yaml:"fields"`
+}
+
+type SpecField struct {
+	Name           string   `yaml:"name"`
+	Type           string   `yaml:"type"`
+	Default        string   `yaml:"default"`
+	Values         []string `yaml:"values"`
+	Table          string   `yaml:"table"`
+	Unique         bool     `yaml:"unique"`
+	NotNull        bool     `yaml:"not_null"`
+	Updateable     bool     `yaml:"updateable"`
+	Precision      int      `yaml:"precision"`
+	Scale          int      `yaml:"scale"`
+	GoType         string   `yaml:"go_type"`
+	Index          bool     `yaml:"index"`
+	Check          string   `yaml:"check"`
+	UniqueTogether []string `yaml:"unique_together"`
+}
+
+// LoadSpec reads a spec file. JSON is a subset of YAML, so both are accepted.
+func LoadSpec(path string) (Spec, error) {
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return Spec{}, fmt.Errorf("failed to read spec %s: %w", path, err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	spec := Spec{}
+
+	if err = decoder.Decode(&spec); err != nil {
+		return Spec{}, fmt.Errorf("failed to parse spec %s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// Inputs validates every resource in the spec and returns them in the order
+// they must be generated: parents before their children, and referenced
+// tables before the resources that reference them. Nothing is returned unless
+// the whole spec is valid.
+//
+//nolint:cyclop,funlen
+func (sp Spec) Inputs(workspaceFolder string) ([]Input, error) {
+	problems := []error{}
+	inputs := []Input{}
+	tables := map[string]int{}
+
+	for _, specService := range sp.Services {
+		if specService.Name == "" {
+			problems = append(problems, fmt.Errorf("service without a name: %w", ErrInvalidSpec))
+
+			continue
+		}
+
+		for _, specResource := range specService.Resources {
+			if specResource.ID == "" {
+				specResource.ID = sp.ID
+			}
+
+			input, resourceProblems := specResource.input(workspaceFolder, specService.Name)
+			problems = append(problems, resourceProblems...)
+
+			table := input.Resource.UnderscorePlural()
+			if _, found := tables[table]; found {
+				problems = append(problems, fmt.Errorf("resource %s is declared more than once: %w", input.Resource, ErrInvalidSpec))
+
+				continue
+			}
+
+			tables[table] = len(inputs)
+			inputs = append(inputs, input)
+		}
+	}
+
+	if len(inputs) == 0 {
+		problems = append(problems, fmt.Errorf("no resources declared: %w", ErrInvalidSpec))
+	}
+
+	// dependencies on tables outside the spec must already exist in the project
+	dependencies := make([][]int, len(inputs))
+
+	for i, input := range inputs {
+		for _, field := range input.Fields {
+			if field.Type != FieldTypeReferences {
+				continue
+			}
+
+			index, found := tables[field.Table]
+			if !found {
+				if !tableExists(workspaceFolder, field.Table) {
+					problems = append(problems, fmt.Errorf("%s.%s: table %s: %w", input.Resource, field.Name, field.Table, ErrUnknownSpecTable))
+				}
+
+				continue
+			}
+
+			if index != i {
+				dependencies[i] = append(dependencies[i], index)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+
+	order, err := dependencyOrder(dependencies)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]Input, 0, len(inputs))
+	for _, index := range order {
+		sorted = append(sorted, inputs[index])
+	}
+
+	return sorted, nil
+}
+
+//nolint:cyclop
+func (r SpecResource) input(workspaceFolder string, service string) (Input, []error) {
+	problems := []error{}
+
+	if err := ensureValidResourceName(r.Name); err != nil {
+		problems = append(problems, fmt.Errorf("resource %q: %w", r.Name, err))
+	}
+
+	input := Input{
+		WorkspaceFolder: workspaceFolder,
+		Service:         TemplateName(service),
+		Resource:        TemplateName(r.Name),
+		SearchField:     r.SearchField,
+		HasSearch:       r.SearchField != "",
+		Nested:          r.Nested,
+		SoftDelete:      r.SoftDelete,
+		Bulk:            r.Bulk,
+		UpsertKey:       r.UpsertKey,
+	}
+
+	id, err := ParseIDStrategy(r.ID)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("resource %q: %w", r.Name, err))
+	}
+
+	input.ID = id
+
+	pagination, err := ParsePagination(r.Pagination)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("resource %q: %w", r.Name, err))
+	}
+
+	input.Pagination = pagination
+
+	fullTextFields, err := ParseSearch(r.Search)
+	if err != nil {
+		problems = append(problems, fmt.Errorf("resource %q: %w", r.Name, err))
+	}
+
+	input.FullTextFields = fullTextFields
+	input.HasSearch = input.HasSearch || len(fullTextFields) > 0
+
+	if r.SearchField != "" && len(fullTextFields) > 0 {
+		problems = append(problems, fmt.Errorf("resource %q: search_field and search can't both be set: %w", r.Name, ErrInvalidSpec))
+	}
+
+	for _, specField := range r.Fields {
+		field, err := specField.inputField(service, r.Name)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s.%s: %w", r.Name, specField.Name, err))
+
+			continue
+		}
+
+		input.Fields = append(input.Fields, field)
+	}
+
+	if r.Parent != "" {
+		parentName := TemplateName(r.Parent)
+		input.Parent = &parentName
+		input.Fields = append(input.Fields, ParentField(service, r.Name, r.Parent))
+	} else if r.Nested {
+		problems = append(problems, fmt.Errorf("resource %q: %w", r.Name, ErrNestedWithoutParent))
+	}
+
+	if err := input.ensureConstraintFields(input.columnNames()); err != nil {
+		problems = append(problems, err)
+	}
+
+	if r.SearchField != "" {
+		found := false
+
+		for _, field := range input.Fields {
+			if field.Name.String() == r.SearchField {
+				found = field.Type == FieldTypeString
+
+				break
+			}
+		}
+
+		if !found {
+			problems = append(problems, fmt.Errorf("%s: search field %s is not a string field: %w", r.Name, r.SearchField, ErrInvalidSpec))
+		}
+	}
+
+	if err := input.ensureFullTextFields(); err != nil {
+		problems = append(problems, err)
+	}
+
+	if err := input.ensureBulkFields(); err != nil {
+		problems = append(problems, err)
+	}
+
+	return input, problems
+}
+
+func (f SpecField) inputField(service string, resource string) (InputField, error) {
+	fieldType, isArray, err := parseFieldType(f.Type)
+	if err != nil {
+		return InputField{}, fmt.Errorf("unknown type %q: %w", f.Type, err)
+	}
+
+	return normalizeField(InputField{
+		Service:        TemplateName(service),
+		Resource:       TemplateName(resource),
+		Name:           TemplateName(f.Name),
+		Type:           fieldType,
+		Default:        f.Default,
+		EnumValues:     f.Values,
+		Table:          f.Table,
+		Unique:         f.Unique,
+		NotNull:        f.NotNull,
+		Updateable:     f.Updateable || fieldType == FieldTypeAttachment,
+		Precision:      f.Precision,
+		Scale:          f.Scale,
+		JSONType:       f.GoType,
+		Array:          isArray,
+		Index:          f.Index,
+		Check:          f.Check,
+		UniqueTogether: f.UniqueTogether,
+	})
+}
+
+// dependencyOrder sorts the indices topologically, keeping the declared order
+// wherever the dependencies allow it.
+func dependencyOrder(dependencies [][]int) ([]int, error) {
+	done := make([]bool, len(dependencies))
+	order := make([]int, 0, len(dependencies))
+
+	for len(order) < len(dependencies) {
+		progressed := false
+
+		for i, deps := range dependencies {
+			if done[i] {
+				continue
+			}
+
+			ready := true
+
+			for _, dep := range deps {
+				if !done[dep] {
+					ready = false
+
+					break
+				}
+			}
+
+			if ready {
+				done[i] = true
+				order = append(order, i)
+				progressed = true
+
+				break
+			}
+		}
+
+		if !progressed {
+			return nil, ErrCyclicSpec
+		}
+	}
+
+	return order, nil
+}
+
+// tableExists looks for the table in the schema dump and the up migrations of
+// the project.
+func tableExists(workspaceFolder string, table string) bool {
+	pattern := regexp.MustCompile(`(?i)CREATE TABLE (IF NOT EXISTS )?(public\.)?"?` + regexp.QuoteMeta(table) + `"?\s*\(`)
+
+	paths, _ := filepath.Glob(filepath.Join(workspaceFolder, "migrations", "*.up.sql"))
+	paths = append(paths, filepath.Join(workspaceFolder, "internal", "database", "schema.sql"))
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path) //nolint:gosec
+		if err == nil && pattern.Match(content) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package generator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadSpecRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+
+	content := "services:\n  - name: blog\n    resources:\n      - name: post\n        fields:\n          - name: title\n            type: string\n            nullable: true\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write spec: %v", err)
+	}
+
+	if _, err := LoadSpec(path); err == nil || !strings.Contains(err.Error(), "nullable") {
+		t.Errorf("expected the unknown key to be reported, got %v", err)
+	}
+}
+
+func TestSpecInputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		resources []SpecResource
+		expected  []string
+		err       error
+	}{
+		{
+			name: "child declared before its parent",
+			resources: []SpecResource{
+				{Name: "Comment", Parent: "Post", Fields: []SpecField{{Name: "body", Type: "string"}}},
+				{Name: "Post", Fields: []SpecField{{Name: "title", Type: "string"}}},
+			},
+			expected: []string{"Post", "Comment"},
+		},
+		{
+			name: "referenced table declared later",
+			resources: []SpecResource{
+				{Name: "Post", Fields: []SpecField{{Name: "author_id", Type: "references", Table: "authors"}}},
+				{Name: "Tag", Fields: []SpecField{{Name: "label", Type: "string"}}},
+				{Name: "Author", Fields: []SpecField{{Name: "name", Type: "string"}}},
+			},
+			expected: []string{"Tag", "Author", "Post"},
+		},
+		{
+			name: "resources referencing each other",
+			resources: []SpecResource{
+				{Name: "Post", Fields: []SpecField{{Name: "author_id", Type: "references", Table: "authors"}}},
+				{Name: "Author", Fields: []SpecField{{Name: "pinned_post_id", Type: "references", Table: "posts"}}},
+			},
+			err: ErrCyclicSpec,
+		},
+		{
+			name: "reference to an undeclared table",
+			resources: []SpecResource{
+				{Name: "Post", Fields: []SpecField{{Name: "author_id", Type: "references", Table: "authors"}}},
+			},
+			err: ErrUnknownSpecTable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := Spec{Services: []SpecService{{Name: "blog", Resources: test.resources}}}
+
+			inputs, err := spec.Inputs(t.TempDir())
+			if test.err != nil {
+				if !errors.Is(err, test.err) {
+					t.Errorf("expected %v, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to read spec: %v", err)
+			}
+
+			resources := []string{}
+			for _, input := range inputs {
+				resources = append(resources, input.Resource.String())
+			}
+
+			if !slices.Equal(resources, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, resources)
+			}
+		})
+	}
+}
+
+func TestDependencyOrder(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies [][]int
+		expected     []int
+		err          error
+	}{
+		{"no dependencies", [][]int{{}, {}, {}}, []int{0, 1, 2}, nil},
+		{"chain", [][]int{{1}, {2}, {}}, []int{2, 1, 0}, nil},
+		{"declared order kept", [][]int{{2}, {}, {}}, []int{1, 2, 0}, nil},
+		{"cycle", [][]int{{1}, {0}}, nil, ErrCyclicSpec},
+		{"cycle behind a dependency", [][]int{{}, {2}, {1}, {0}}, nil, ErrCyclicSpec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := dependencyOrder(test.dependencies)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected %v, got %v", test.err, err)
+			}
+
+			if !slices.Equal(order, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, order)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidSQLCConfig = errors.New("invalid sqlc.yaml")
+
+type sqlcOverride struct {
+	Column string     `yaml:"column"`
+	GoType sqlcGoType `yaml:"go_type"`
+}
+
+type sqlcGoType struct {
+	Import  string `yaml:"import,omitempty"`
+	Type    string `yaml:"type"`
+	Pointer bool   `yaml:"pointer,omitempty"`
+}
+
+// generateJSONTypes writes a struct for every json field that doesn't have
+// one yet, and points sqlc at the field's type with a column override. The
+// search vector of full-text searched resources is overridden along with
+// them.
+func (s *Service) generateJSONTypes(_ context.Context, input Input) error {
+	overrides := []sqlcOverride{}
+
+	for _, field := range input.Fields {
+		if field.Type != FieldTypeJSON {
+			continue
+		}
+
+		if field.JSONShape == nil && strings.HasSuffix(field.JSONImportPath(), "/internal/types") {
+			folderPath := filepath.Join(input.WorkspaceFolder, "internal", "types")
+
+			if err := s.ensureFolderExists(folderPath); err != nil {
+				return fmt.Errorf("failed to ensure types folder exists: %w", err)
+			}
+
+			if err := s.ensureFileExists(jsonTypeFilePath(input.WorkspaceFolder, field), "json_type", field); err != nil {
+				return fmt.Errorf("failed to write %s: %w", field.JSONTypeName(), err)
+			}
+		}
+
+		overrides = append(overrides, sqlcOverride{
+			Column: input.Resource.UnderscorePlural() + "." + field.Name.String(),
+			GoType: sqlcGoType{
+				Import:  field.JSONImportPath(),
+				Type:    field.JSONTypeName(),
+				Pointer: !field.NotNull,
+			},
+		})
+	}
+
+	overrides = append(overrides, input.searchVectorOverrides()...)
+
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	return s.updateSQLCOverrides(input.WorkspaceFolder, func(existing []sqlcOverrideNode) ([]*yaml.Node, error) {
+		nodes := []*yaml.Node{}
+
+		for _, override := range existing {
+			if !lo.SomeBy(overrides, func(o sqlcOverride) bool { return o.Column == override.column }) {
+				nodes = append(nodes, override.node)
+			}
+		}
+
+		for _, override := range overrides {
+			node := &yaml.Node{}
+			if err := node.Encode(override); err != nil {
+				return nil, fmt.Errorf("failed to encode sqlc override: %w", err)
+			}
+
+			nodes = append(nodes, node)
+		}
+
+		return nodes, nil
+	})
+}
+
+// removeJSONTypes drops the sqlc column overrides of a resource, and the
+// structs oxgen generated for its json fields.
+func (s *Service) removeJSONTypes(input Input) error {
+	for _, field := range input.Fields {
+		if field.Type == FieldTypeJSON && field.JSONTypeName() == field.defaultJSONTypeName() && strings.HasSuffix(field.JSONImportPath(), "/internal/types") {
+			if err := s.removeFile(jsonTypeFilePath(input.WorkspaceFolder, field)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !s.fileExists(filepath.Join(input.WorkspaceFolder, "sqlc.yaml")) {
+		return nil
+	}
+
+	prefix := input.Resource.UnderscorePlural() + "."
+
+	return s.updateSQLCOverrides(input.WorkspaceFolder, func(existing []sqlcOverrideNode) ([]*yaml.Node, error) {
+		nodes := []*yaml.Node{}
+
+		for _, override := range existing {
+			if !strings.HasPrefix(override.column, prefix) {
+				nodes = append(nodes, override.node)
+			}
+		}
+
+		return nodes, nil
+	})
+}
+
+func jsonTypeFilePath(workspaceFolder string, field InputField) string {
+	return filepath.Join(workspaceFolder, "internal", "types", field.Resource.UnderscoreSingular()+"_"+strcase.ToSnake(field.Name.String())+".go")
+}
+
+type sqlcOverrideNode struct {
+	column string
+	node   *yaml.Node
+}
+
+// updateSQLCOverrides rewrites the Go overrides of every sql entry in
+// sqlc.yaml. The file is edited as a YAML node tree, so the rest of it and
+// its comments are kept, and it is only written if the overrides changed.
+//
+//nolint:cyclop
+func (s *Service) updateSQLCOverrides(workspaceFolder string, update func([]sqlcOverrideNode) ([]*yaml.Node, error)) error {
+	configPath := filepath.Join(workspaceFolder, "sqlc.yaml")
+
+	content, err := s.readFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	document := &yaml.Node{}
+	if err = yaml.Unmarshal(content, document); err != nil {
+		return fmt.Errorf("failed to parse sqlc.yaml: %w", err)
+	}
+
+	if len(document.Content) == 0 {
+		return ErrInvalidSQLCConfig
+	}
+
+	sqlEntries := mappingValue(document.Content[0], "sql")
+	if sqlEntries == nil || sqlEntries.Kind != yaml.SequenceNode {
+		return fmt.Errorf("sql entries missing: %w", ErrInvalidSQLCConfig)
+	}
+
+	changed := false
+
+	for _, entry := range sqlEntries.Content {
+		goConfig := mappingValue(mappingValue(entry, "gen"), "go")
+		if goConfig == nil {
+			continue
+		}
+
+		overridesNode := mappingValue(goConfig, "overrides")
+		if overridesNode == nil {
+			overridesNode = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			goConfig.Content = append(goConfig.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "overrides"}, overridesNode)
+		}
+
+		existing := []sqlcOverrideNode{}
+
+		for _, node := range overridesNode.Content {
+			column := ""
+			if columnNode := mappingValue(node, "column"); columnNode != nil {
+				column = columnNode.Value
+			}
+
+			existing = append(existing, sqlcOverrideNode{column: column, node: node})
+		}
+
+		updated, err := update(existing)
+		if err != nil {
+			return err
+		}
+
+		if len(updated) != len(existing) || lo.SomeBy(updated, func(node *yaml.Node) bool {
+			return !lo.ContainsBy(existing, func(override sqlcOverrideNode) bool { return override.node == node })
+		}) {
+			overridesNode.Content = updated
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2) //nolint:gomnd
+
+	if err = encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to encode sqlc.yaml: %w", err)
+	}
+
+	return s.writeFile(configPath, buf.Bytes())
+}
+
+// mappingValue looks up a key in a YAML mapping node.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package generator
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// TemplateNames lists the names of the built-in templates.
+func TemplateNames() []string {
+	names := []string{}
+
+	for name := range templateFiles() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// BuiltinTemplate returns the file name and content of a built-in template.
+func BuiltinTemplate(name string) (string, []byte, error) {
+	fileName, found := templateFiles()[name]
+	if !found {
+		return "", nil, fmt.Errorf("%s: %w", name, ErrTemplateNotFound)
+	}
+
+	content, err := builtinTemplates.ReadFile("templates/" + fileName)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+
+	return fileName, content, nil
+}
+
+// templateFiles maps each built-in template name to its file. The name is the
+// file name up to the first dot, so create_service_method.go.tmpl is named
+// create_service_method.
+func templateFiles() map[string]string {
+	files := map[string]string{}
+
+	entries, _ := builtinTemplates.ReadDir("templates")
+	for _, entry := range entries {
+		files[templateName(entry.Name())] = entry.Name()
+	}
+
+	return files
+}
+
+func templateName(fileName string) string {
+	return strings.SplitN(fileName, ".", 2)[0] //nolint:gomnd
+}
+
+// loadTemplates parses every built-in template into a single set, so they can
+// include each other, with files in the templates folder replacing the
+// built-ins of the same name.
+func (s *Service) loadTemplates() (*template.Template, error) {
+	if s.templates != nil {
+		return s.templates, nil
+	}
+
+	sources := map[string]string{}
+
+	for name, fileName := range templateFiles() {
+		content, err := builtinTemplates.ReadFile("templates/" + fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", name, err)
+		}
+
+		sources[name] = string(content)
+	}
+
+	if s.templatesFolder != "" {
+		entries, err := os.ReadDir(s.templatesFolder)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read templates folder: %w", err)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tmpl") {
+				continue
+			}
+
+			content, err := os.ReadFile(filepath.Join(s.templatesFolder, entry.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+			}
+
+			sources[templateName(entry.Name())] = string(content)
+		}
+	}
+
+	templates := template.New("")
+
+	for name, source := range sources {
+		if _, err := templates.New(name).Parse(source); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+	}
+
+	s.templates = templates
+
+	return templates, nil
+}
+
+func (s *Service) renderTemplate(templateName string, input any) ([]byte, error) {
+	templates, err := s.loadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	if templates.Lookup(templateName) == nil {
+		return nil, fmt.Errorf("%s: %w", templateName, ErrTemplateNotFound)
+	}
+
+	buf := &strings.Builder{}
+	if err = templates.ExecuteTemplate(buf, templateName, input); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", templateName, err)
+	}
+
+	return []byte(buf.String()), nil
+}
@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var ErrParentConflicts = errors.New("parent files have conflicting edits")
+
+// mainDBLine is where main.go makes the queries the services are given,
+// before they are made through the transaction aware provider.
+const mainDBLine = "db := dbx.New(dbDriver.DB())"
+
+// ChildResource is a resource with another as its parent, whose rows are
+// destroyed along with their parent's.
+type ChildResource struct {
+	Name TemplateName
+	ID   IDStrategy
+}
+
+// DestroysChildren is true if destroying the resource's rows also destroys
+// their children's rows. Soft deleted rows keep theirs, hidden if they are
+// nested, until they are purged.
+func (i Input) DestroysChildren() bool {
+	return !i.SoftDelete && len(i.Children) > 0
+}
+
+// PurgesChildren is true if purging a soft deleted resource's rows has to
+// delete their children's rows first, which reference them.
+func (i Input) PurgesChildren() bool {
+	return i.SoftDelete && len(i.Children) > 0
+}
+
+// DestroyInTx is true if destroying an item takes more than one statement:
+// checking that it is under its parent, or destroying its children.
+func (i Input) DestroyInTx() bool {
+	return i.Nested || i.DestroysChildren()
+}
+
+// resolveChildren finds the resources that have the resource as their parent
+// in the manifest, whether they are nested under it or not.
+func (s *Service) resolveChildren(input Input) (Input, error) {
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return Input{}, err
+	}
+
+	input.Children = []ChildResource{}
+
+	for _, entry := range manifest.Resources {
+		if entry.Parent == input.Resource.String() {
+			input.Children = append(input.Children, ChildResource{
+				Name: TemplateName(entry.Resource),
+				ID:   entry.ID.orDefault(),
+			})
+		}
+	}
+
+	return input, nil
+}
+
+func databaseProviderFilePath(workspaceFolder string) string {
+	return filepath.Join(workspaceFolder, "internal", "database", "provider.go")
+}
+
+// ensureTransactions adds the provider that runs queries in transactions the
+// first time a resource is generated, and gives it to the services in place
+// of the bare queries.
+func (s *Service) ensureTransactions(input Input) error {
+	path := databaseProviderFilePath(input.WorkspaceFolder)
+
+	if s.fileExists(path) {
+		return nil
+	}
+
+	if err := s.ensureFileExists(path, "database_provider", input); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(filepath.Dir(path), "goimports", "-w", filepath.Base(path)); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	ifaceFolderPath := filepath.Join(input.WorkspaceFolder, "internal", "service")
+
+	if err := s.injectIntoInterface(filepath.Join(ifaceFolderPath, "database_iface.go"), "DatabaseProvider", "database_tx_iface", input); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(ifaceFolderPath, "goimports", "-w", "database_iface.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	mainFilePath := filepath.Join(input.WorkspaceFolder, "main.go")
+
+	if err := s.injectTemplateBelowLine(mainFilePath, mainDBLine, "main_database_provider", input); err != nil {
+		return fmt.Errorf("failed to give the services the database provider: %w", err)
+	}
+
+	if err := s.removeLinesFromFile(mainFilePath, func(line string) bool {
+		return strings.TrimSpace(line) == mainDBLine
+	}); err != nil {
+		return err
+	}
+
+	if err := s.runCommand(input.WorkspaceFolder, "goimports", "-w", "main.go"); err != nil {
+		return fmt.Errorf("failed running goimports: %w", err)
+	}
+
+	return nil
+}
+
+// regenerateParent merges the files of a resource's parent again, so that
+// destroying or purging the parent's rows destroys the resource's along with
+// them.
+func (s *Service) regenerateParent(ctx context.Context, input Input) error {
+	if input.Parent == nil {
+		return nil
+	}
+
+	manifest, err := s.LoadManifest(input.WorkspaceFolder)
+	if err != nil {
+		return err
+	}
+
+	entry, found := manifest.Find(input.Parent.String())
+	if !found {
+		return nil
+	}
+
+	conflicted, err := s.mergeResourceFiles(ctx, entry.Input(input.WorkspaceFolder))
+	if err != nil {
+		return err
+	}
+
+	if len(conflicted) > 0 {
+		return fmt.Errorf("%s: %s: %w", *input.Parent, strings.Join(conflicted, ", "), ErrParentConflicts)
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package generator
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/iancoleman/strcase"
@@ -15,9 +17,34 @@ type Input struct {
 	Service         TemplateName
 	Resource        TemplateName
 	Parent          *TemplateName
-	SearchField     string
-	HasSearch       bool
-	Fields          []InputField
+	// Nested resources have all their routes under their parent's, and are
+	// only fetched, updated and destroyed along with their parent's id.
+	Nested bool
+	// SoftDelete resources are only marked as deleted by destroy, and can be
+	// restored until they are purged.
+	SoftDelete bool
+	// ID is how the keys of the resource's table are typed and generated,
+	// and ParentID how its parent's are.
+	ID       IDStrategy
+	ParentID IDStrategy
+	// ParentSoftDelete is set when the parent is soft deleted, whose deleted
+	// rows hide the rows nested under them.
+	ParentSoftDelete bool
+	// Pagination is how the recent and search listings are paged.
+	Pagination  Pagination
+	SearchField string
+	// FullTextFields are the fields searched with Postgres full-text search,
+	// instead of ILIKE on the SearchField.
+	FullTextFields []string
+	HasSearch      bool
+	// Bulk resources have queries and endpoints creating and deleting many
+	// items at once, and upserting them on their UpsertKey if they have one.
+	Bulk      bool
+	UpsertKey string
+	// Children are the resources nested under this one, found in the
+	// manifest.
+	Children []ChildResource
+	Fields   []InputField
 }
 
 type InputField struct {
@@ -32,6 +59,39 @@ type InputField struct {
 	Unique     bool
 	Updateable bool
 	NotNull    bool
+	Precision  int
+	Scale      int
+	Array      bool
+	// Index adds an index on the column. References are always indexed.
+	Index bool
+	// UniqueTogether are the other columns the field is unique along with.
+	UniqueTogether []string
+	// Check is an SQL expression the column's values must satisfy.
+	Check string
+	// JSONType is the Go type a json field is decoded into, as an import path
+	// and type name such as github.com/acme/app/internal/types.Settings.
+	JSONType string
+	// JSONShape is the TypeScript counterpart of JSONType, read from its Go
+	// declaration when it is in the project.
+	JSONShape []TypescriptInterface
+	// Includable is set on references fields whose records can be embedded
+	// in the resource's responses with ?include=.
+	Includable bool
+	// NestedIn is the parent of a nested resource, whose routes the field's
+	// routes are nested under.
+	NestedIn *TemplateName
+	// SoftDelete is set on fields of soft deleted resources, whose queries
+	// leave out deleted rows.
+	SoftDelete bool
+	// ResourceID and ParentID are the key strategies of the field's resource
+	// and of its parent.
+	ResourceID IDStrategy
+	ParentID   IDStrategy
+	// ParentSoftDelete is set on fields of resources nested under a soft
+	// deleted parent.
+	ParentSoftDelete bool
+	// TableID is the key strategy of the table a references field points at.
+	TableID IDStrategy
 }
 
 type FieldType string
@@ -40,6 +100,10 @@ const (
 	FieldTypeString     FieldType = "string"
 	FieldTypeEnum       FieldType = "enum"
 	FieldTypeInt        FieldType = "int"
+	FieldTypeBigint     FieldType = "bigint"
+	FieldTypeFloat      FieldType = "float"
+	FieldTypeDecimal    FieldType = "decimal"
+	FieldTypeJSON       FieldType = "json"
 	FieldTypeBool       FieldType = "bool"
 	FieldTypeDate       FieldType = "date"
 	FieldTypeTimestamp  FieldType = "timestamp"
@@ -49,7 +113,19 @@ const (
 	FieldTypeUnknown    FieldType = "unknown"
 )
 
-//nolint:funlen,revive,cyclop
+// arrayElementTypes are the field types that can be declared as arrays.
+//
+//nolint:gochecknoglobals
+var arrayElementTypes = []FieldType{
+	FieldTypeString,
+	FieldTypeInt,
+	FieldTypeBigint,
+	FieldTypeFloat,
+	FieldTypeBool,
+	FieldTypeUUID,
+}
+
+//nolint:revive,cyclop
 func ParseField(service string, resource string, fieldString string) (InputField, error) {
 	field := InputField{}
 
@@ -63,34 +139,16 @@ func ParseField(service string, resource string, fieldString string) (InputField
 	field.Service = TemplateName(service)
 	field.Resource = TemplateName(resource)
 	field.Name = TemplateName(words[0])
-	field.Updateable = false
-
-	fieldTypeString := words[1]
 
-	switch fieldTypeString {
-	case "string": //nolint:goconst
-		field.Type = FieldTypeString
-	case "enum":
-		field.Type = FieldTypeEnum
-	case "int":
-		field.Type = FieldTypeInt
-	case "bool": //nolint:goconst
-		field.Type = FieldTypeBool
-	case "uuid": //nolint:goconst
-		field.Type = FieldTypeUUID
-	case "references":
-		field.Type = FieldTypeReferences
-	case "attachment":
-		field.Type = FieldTypeAttachment
-		field.Updateable = true
-	case "date":
-		field.Type = FieldTypeDate
-	case "timestamp":
-		field.Type = FieldTypeTimestamp
-	default:
-		return InputField{}, ErrInvalidResourceField
+	fieldType, isArray, err := parseFieldType(words[1])
+	if err != nil {
+		return InputField{}, err
 	}
 
+	field.Type = fieldType
+	field.Array = isArray
+	field.Updateable = fieldType == FieldTypeAttachment
+
 	for _, word := range words[2:] {
 		switch {
 		case strings.HasPrefix(word, "default="):
@@ -102,8 +160,24 @@ func ParseField(service string, resource string, fieldString string) (InputField
 		case strings.HasPrefix(word, "values="):
 			kvWords := strings.Split(word, "=")
 			field.EnumValues = strings.Split(kvWords[1], ",")
+		case strings.HasPrefix(word, "go_type="):
+			field.JSONType = strings.TrimPrefix(word, "go_type=")
+		case strings.HasPrefix(word, "precision="):
+			if field.Precision, err = strconv.Atoi(strings.TrimPrefix(word, "precision=")); err != nil {
+				return InputField{}, ErrInvalidResourceField
+			}
+		case strings.HasPrefix(word, "scale="):
+			if field.Scale, err = strconv.Atoi(strings.TrimPrefix(word, "scale=")); err != nil {
+				return InputField{}, ErrInvalidResourceField
+			}
+		case strings.HasPrefix(word, "unique_together="):
+			field.UniqueTogether = strings.Split(strings.TrimPrefix(word, "unique_together="), ",")
+		case strings.HasPrefix(word, "check="):
+			field.Check = strings.TrimPrefix(word, "check=")
 		case word == "unique":
 			field.Unique = true
+		case word == "index":
+			field.Index = true
 		case word == "not_null":
 			field.NotNull = true
 		case word == "updateable":
@@ -113,6 +187,97 @@ func ParseField(service string, resource string, fieldString string) (InputField
 		}
 	}
 
+	return normalizeField(field)
+}
+
+// withResourceOptions copies the options of the resource that field
+// templates depend on to each of its fields.
+func (i Input) withResourceOptions() Input {
+	fields := append([]InputField{}, i.Fields...)
+
+	for j := range fields {
+		fields[j].NestedIn = nil
+
+		if i.Nested {
+			fields[j].NestedIn = i.Parent
+		}
+
+		fields[j].SoftDelete = i.SoftDelete
+		fields[j].ResourceID = i.ID.orDefault()
+		fields[j].ParentID = i.ParentID.orDefault()
+		fields[j].ParentSoftDelete = i.ParentSoftDelete
+	}
+
+	i.Fields = fields
+
+	return i
+}
+
+// ParentField is the references field that ties a child resource to its parent.
+func ParentField(service string, resource string, parent string) InputField {
+	parentName := TemplateName(parent)
+
+	return InputField{
+		Service:  TemplateName(service),
+		Resource: TemplateName(resource),
+		Name:     TemplateName(parentName.UnderscoreSingular() + "_id"),
+		Type:     FieldTypeReferences,
+		Required: true,
+		Table:    parentName.UnderscorePlural(),
+		NotNull:  true,
+	}
+}
+
+// parseFieldType reads a field type, where a [] suffix makes it an array of
+// that type.
+func parseFieldType(fieldTypeString string) (FieldType, bool, error) {
+	elementTypeString, isArray := strings.CutSuffix(fieldTypeString, "[]")
+
+	fieldType, err := parseElementType(elementTypeString)
+
+	return fieldType, isArray, err
+}
+
+func parseElementType(fieldTypeString string) (FieldType, error) {
+	switch fieldTypeString {
+	case "string": //nolint:goconst
+		return FieldTypeString, nil
+	case "enum":
+		return FieldTypeEnum, nil
+	case "int":
+		return FieldTypeInt, nil
+	case "bigint":
+		return FieldTypeBigint, nil
+	case "float":
+		return FieldTypeFloat, nil
+	case "decimal":
+		return FieldTypeDecimal, nil
+	case "json":
+		return FieldTypeJSON, nil
+	case "bool": //nolint:goconst
+		return FieldTypeBool, nil
+	case "uuid": //nolint:goconst
+		return FieldTypeUUID, nil
+	case "references":
+		return FieldTypeReferences, nil
+	case "attachment":
+		return FieldTypeAttachment, nil
+	case "date":
+		return FieldTypeDate, nil
+	case "timestamp":
+		return FieldTypeTimestamp, nil
+	default:
+		return FieldTypeUnknown, ErrInvalidResourceField
+	}
+}
+
+// normalizeField applies the rules every field must satisfy, however it was
+// declared.
+func normalizeField(field InputField) (InputField, error) {
+	if field.Name == "" {
+		return InputField{}, ErrInvalidResourceField
+	}
+
 	if field.Type == FieldTypeEnum {
 		if len(field.EnumValues) == 0 {
 			return InputField{}, ErrInvalidResourceField
@@ -121,10 +286,46 @@ func ParseField(service string, resource string, fieldString string) (InputField
 		field.NotNull = true
 	}
 
+	if field.Type == FieldTypeReferences && field.Table == "" {
+		return InputField{}, ErrInvalidResourceField
+	}
+
+	// precision and scale only apply to decimals, and the scale can't exceed
+	// the precision
+	if field.Type != FieldTypeDecimal && (field.Precision != 0 || field.Scale != 0) {
+		return InputField{}, ErrInvalidResourceField
+	}
+
+	if field.JSONType != "" && (field.Type != FieldTypeJSON || !validJSONType(field.JSONType)) {
+		return InputField{}, ErrInvalidResourceField
+	}
+
+	if field.Array && !lo.Contains(arrayElementTypes, field.Type) {
+		return InputField{}, ErrInvalidResourceField
+	}
+
+	if lo.Contains(field.UniqueTogether, "") {
+		return InputField{}, ErrInvalidResourceField
+	}
+
+	if field.Precision < 0 || field.Scale < 0 || (field.Scale > 0 && field.Scale > field.Precision) {
+		return InputField{}, ErrInvalidResourceField
+	}
+
 	return field, nil
 }
 
 func (f InputField) SQLType() string {
+	if f.Array {
+		return f.ElementSQLType() + "[]"
+	}
+
+	return f.ElementSQLType()
+}
+
+// ElementSQLType is the SQL type of the field, or of its elements if it is an
+// array.
+func (f InputField) ElementSQLType() string {
 	switch f.Type {
 	case FieldTypeString:
 		return "text"
@@ -132,12 +333,20 @@ func (f InputField) SQLType() string {
 		return f.Resource.UnderscoreSingular() + "_" + f.Name.UnderscoreSingular()
 	case FieldTypeInt:
 		return "integer"
+	case FieldTypeBigint:
+		return "bigint"
+	case FieldTypeFloat:
+		return "double precision"
+	case FieldTypeDecimal:
+		return f.numericSQLType()
+	case FieldTypeJSON:
+		return "jsonb"
 	case FieldTypeBool:
 		return "bool"
 	case FieldTypeUUID:
 		return "uuid"
 	case FieldTypeReferences:
-		return "uuid"
+		return f.TableID.SQLType()
 	case FieldTypeAttachment:
 		return "text"
 	case FieldTypeDate:
@@ -151,17 +360,40 @@ func (f InputField) SQLType() string {
 	}
 }
 
+// numericSQLType is numeric, numeric(p) or numeric(p,s), depending on which
+// of precision and scale were given.
+func (f InputField) numericSQLType() string {
+	switch {
+	case f.Precision == 0:
+		return "numeric"
+	case f.Scale == 0:
+		return fmt.Sprintf("numeric(%d)", f.Precision)
+	default:
+		return fmt.Sprintf("numeric(%d,%d)", f.Precision, f.Scale)
+	}
+}
+
 func (f InputField) EnumTypesCreateSQL() string {
 	if f.Type != FieldTypeEnum {
 		return ""
 	}
 
-	evStrings := lo.Map(f.EnumValues, func(s string, _ int) string { return "'" + s + "'" })
+	evStrings := lo.Map(f.EnumValues, func(s string, _ int) string { return sqlQuote(s) })
 
 	return "CREATE TYPE " + f.SQLType() + " AS ENUM (\n" + strings.Join(evStrings, ",\n") + "\n);"
 }
 
 func (f InputField) GoType() string {
+	if f.Array {
+		return "[]" + f.ElementGoType()
+	}
+
+	return f.ElementGoType()
+}
+
+// ElementGoType is the Go type of the field, or of its elements if it is an
+// array.
+func (f InputField) ElementGoType() string {
 	switch f.Type {
 	case FieldTypeString:
 		return "string"
@@ -169,12 +401,20 @@ func (f InputField) GoType() string {
 		return "dbx." + f.Resource.CamelcaseSingular() + f.Name.CamelcaseSingular()
 	case FieldTypeInt:
 		return "int32"
+	case FieldTypeBigint:
+		return "int64"
+	case FieldTypeFloat:
+		return "float64"
+	case FieldTypeDecimal:
+		return "pgtype.Numeric"
+	case FieldTypeJSON:
+		return f.jsonGoType()
 	case FieldTypeBool:
 		return "bool"
 	case FieldTypeUUID:
 		return "uuid.UUID" //nolint:goconst
 	case FieldTypeReferences:
-		return "uuid.UUID"
+		return f.TableID.GoType()
 	case FieldTypeAttachment:
 		return "string"
 	case FieldTypeDate:
@@ -189,6 +429,14 @@ func (f InputField) GoType() string {
 }
 
 func (f InputField) PresenterGoType() string {
+	if f.Array {
+		return "[]" + f.elementPresenterGoType()
+	}
+
+	return f.elementPresenterGoType()
+}
+
+func (f InputField) elementPresenterGoType() string {
 	switch f.Type {
 	case FieldTypeString:
 		return "string"
@@ -196,12 +444,20 @@ func (f InputField) PresenterGoType() string {
 		return "string"
 	case FieldTypeInt:
 		return "int32"
+	case FieldTypeBigint:
+		return "int64"
+	case FieldTypeFloat:
+		return "float64"
+	case FieldTypeDecimal:
+		return "pgtype.Numeric"
+	case FieldTypeJSON:
+		return f.jsonGoType()
 	case FieldTypeBool:
 		return "bool"
 	case FieldTypeUUID:
 		return "string"
 	case FieldTypeReferences:
-		return "string"
+		return f.TableID.PresenterGoType()
 	case FieldTypeAttachment:
 		return "string"
 	case FieldTypeDate:
@@ -223,18 +479,43 @@ func (f InputField) JSONName() string {
 	return strcase.ToLowerCamel(f.Name.String())
 }
 
+// GoName is the name of the field in the params, request and presenter
+// structs. Array columns keep their plural name, as sqlc doesn't singularize
+// the fields it generates for them.
+func (f InputField) GoName() string {
+	if f.Array {
+		return sqlcFieldName(f.Name.String())
+	}
+
+	return f.Name.CamelcaseSingular()
+}
+
+// FrontendName is the name of the field in the frontend model, which is the
+// key it is presented under for array columns.
+func (f InputField) FrontendName() string {
+	if f.Array {
+		return f.JSONName()
+	}
+
+	return f.Name.LowerCamelcaseSingular()
+}
+
 func (f InputField) CreateSQLFragment() string {
-	fragment := "  " + f.Name.String() + " " + f.SQLType()
+	return "  " + f.ColumnSQLFragment()
+}
+
+func (f InputField) ColumnSQLFragment() string {
+	fragment := f.Name.String() + " " + f.SQLType()
 
 	if f.Type == FieldTypeReferences {
 		fragment += (" REFERENCES " + f.Table + "(id)")
 	}
 
 	if f.Default != "" {
-		if f.Type == FieldTypeString {
-			fragment += (`"` + f.Default + `"`)
+		if f.Type == FieldTypeString || f.Type == FieldTypeEnum || f.Type == FieldTypeJSON || f.Array {
+			fragment += " DEFAULT " + sqlQuote(f.Default)
 		} else {
-			fragment += f.Default
+			fragment += " DEFAULT " + f.Default
 		}
 	}
 
@@ -246,6 +527,10 @@ func (f InputField) CreateSQLFragment() string {
 		fragment += " UNIQUE"
 	}
 
+	if f.Check != "" {
+		fragment += " CHECK (" + f.Check + ")"
+	}
+
 	return fragment
 }
 
@@ -254,21 +539,21 @@ func (f InputField) JSONTag() string {
 }
 
 func (f InputField) CreateParamsGoFragment() string {
-	fragment := "  " + f.Name.CamelcaseSingular() + " " + f.GoType()
+	fragment := "  " + f.GoName() + " " + f.GoType()
 
 	return fragment
 }
 
 func (f InputField) CreateRequestGoFragment() string {
-	fragment := "  " + f.Name.CamelcaseSingular() + " " + f.GoType() + " " + f.JSONTag()
+	fragment := "  " + f.GoName() + " " + f.GoType() + " " + f.JSONTag()
 
 	return fragment
 }
 
 func (f InputField) PresenterGoFragment() string {
-	fragment := "  " + f.Name.CamelcaseSingular() + " "
+	fragment := "  " + f.GoName() + " "
 
-	if !f.NotNull {
+	if !f.NotNull && !f.Array {
 		fragment += "*"
 	}
 
@@ -278,25 +563,25 @@ func (f InputField) PresenterGoFragment() string {
 }
 
 func (f InputField) CreateAssignParamsGoFragment() string {
-	dbxName := f.Name.CamelcaseSingular()
+	dbxName := f.GoName()
 
 	if strings.HasSuffix(dbxName, "Id") {
 		dbxName = dbxName[:len(dbxName)-2] + "ID"
 	}
 
-	fragment := "  " + dbxName + ": params." + f.Name.CamelcaseSingular()
+	fragment := "  " + dbxName + ": params." + f.GoName()
 
 	return fragment
 }
 
 func (f InputField) CreateHandlerAssignParamsGoFragment() string {
-	fragment := "  input." + f.Name.CamelcaseSingular() + " = request." + f.Name.CamelcaseSingular()
+	fragment := "  input." + f.GoName() + " = request." + f.GoName()
 
 	return fragment
 }
 
 func (f InputField) UpdateAssignParamGoFragment() string {
-	if f.NotNull {
+	if f.NotNull || f.Array {
 		return f.Name.String() + " = @" + f.Name.String() + "::" + f.SQLType()
 	}
 
@@ -306,7 +591,11 @@ func (f InputField) UpdateAssignParamGoFragment() string {
 func (f InputField) UpdateGoFunctionSignatureParam() string {
 	paramString := "value "
 
-	if !f.NotNull {
+	switch {
+	case f.NotNull, f.Array:
+	case f.UpdateValueIsParam():
+		paramString = "value *"
+	default:
 		paramString = "valuePtr *"
 	}
 
@@ -315,9 +604,31 @@ func (f InputField) UpdateGoFunctionSignatureParam() string {
 	return paramString
 }
 
-//nolint:funlen
+// UpdateValueIsParam is true when the update method can pass its parameter to
+// the query as it is. Nullable json columns are pointers in dbx, and arrays
+// are slices that are nil for NULL, so they don't need wrapping in a pgtype.
+func (f InputField) UpdateValueIsParam() bool {
+	return f.NotNull || f.Array || f.Type == FieldTypeJSON
+}
+
+//nolint:funlen,cyclop
 func (f InputField) PresenterAssignment() string {
-	dbxField := f.Name.CamelcaseSingular()
+	dbxField := f.GoName()
+
+	// json columns are decoded into the presented type already
+	if f.Type == FieldTypeJSON {
+		return "  item." + f.GoName() + " = m." + dbxField + "\n\n"
+	}
+
+	if f.Array {
+		if f.Type == FieldTypeUUID {
+			return "for _, id := range m." + dbxField + " {\n" +
+				"item." + f.GoName() + " = append(item." + f.GoName() + ", id.String())\n" +
+				"}\n\n"
+		}
+
+		return "  item." + f.GoName() + " = m." + dbxField + "\n\n"
+	}
 
 	if strings.HasSuffix(dbxField, "Id") {
 		dbxField = dbxField[:len(dbxField)-2] + "ID"
@@ -326,7 +637,11 @@ func (f InputField) PresenterAssignment() string {
 	if f.Type == FieldTypeDate || f.Type == FieldTypeTimestamp || f.Type == FieldTypeReferences {
 		str := ""
 
-		if f.Type == FieldTypeDate || f.Type == FieldTypeTimestamp {
+		// nullable references are left out when unset, rather than presented
+		// as the zero key
+		checkValid := f.Type == FieldTypeDate || f.Type == FieldTypeTimestamp || (f.Type == FieldTypeReferences && !f.NotNull)
+
+		if checkValid {
 			str += "if m." + dbxField + ".Valid {\n"
 		}
 
@@ -335,21 +650,23 @@ func (f InputField) PresenterAssignment() string {
 		switch f.Type {
 		case FieldTypeReferences:
 			if !f.NotNull {
-				str += ".UUID"
+				str += "." + f.PgType()
 			}
 
-			str += ".String()"
+			if f.TableID.PresenterGoType() != f.TableID.GoType() {
+				str += ".String()"
+			}
 		case FieldTypeDate:
 			str += ".Time.Format(\"2006-01-02\")"
 		case FieldTypeTimestamp:
 			str += ".Time.Format(time.RFC3339)"
-		case FieldTypeEnum, FieldTypeString, FieldTypeAttachment, FieldTypeUUID, FieldTypeInt, FieldTypeBool, FieldTypeUnknown:
+		case FieldTypeEnum, FieldTypeString, FieldTypeAttachment, FieldTypeUUID, FieldTypeInt, FieldTypeBigint, FieldTypeFloat, FieldTypeDecimal, FieldTypeJSON, FieldTypeBool, FieldTypeUnknown:
 		default:
 		}
 
 		str += "\n"
 
-		str += "item." + f.Name.CamelcaseSingular() + " = "
+		str += "item." + f.GoName() + " = "
 
 		if !f.NotNull {
 			str += "&"
@@ -357,7 +674,7 @@ func (f InputField) PresenterAssignment() string {
 
 		str += (f.Name.LowerCamelcaseSingular() + "\n")
 
-		if f.Type == FieldTypeDate || f.Type == FieldTypeTimestamp {
+		if checkValid {
 			str += "}\n"
 		}
 
@@ -369,10 +686,10 @@ func (f InputField) PresenterAssignment() string {
 	str := ""
 
 	if !f.NotNull {
-		str += "\nif m." + f.Name.CamelcaseSingular() + ".Valid {\n"
+		str += "\nif m." + f.GoName() + ".Valid {\n"
 	}
 
-	str += "  item." + f.Name.CamelcaseSingular() + " = "
+	str += "  item." + f.GoName() + " = "
 
 	if !f.NotNull {
 		str += "&"
@@ -382,8 +699,9 @@ func (f InputField) PresenterAssignment() string {
 		str += "string("
 	}
 
-	str += ("m." + f.Name.CamelcaseSingular())
-	if !f.NotNull {
+	str += ("m." + f.GoName())
+	// pgtype.Numeric is presented as it is, with its own JSON encoding
+	if !f.NotNull && f.Type != FieldTypeDecimal {
 		str += ("." + f.PgType())
 	}
 
@@ -403,6 +721,10 @@ func (f InputField) PresenterAssignment() string {
 }
 
 func (f InputField) PgType() string {
+	if keyField, found := f.keyField(); found {
+		return keyField.PgType()
+	}
+
 	switch f.Type {
 	case FieldTypeString:
 		return "String"
@@ -412,6 +734,14 @@ func (f InputField) PgType() string {
 		return "String"
 	case FieldTypeInt:
 		return "Int32"
+	case FieldTypeBigint:
+		return "Int64"
+	case FieldTypeFloat:
+		return "Float64"
+	case FieldTypeDecimal:
+		return "Numeric"
+	case FieldTypeJSON:
+		return f.JSONTypeName()
 	case FieldTypeBool:
 		return "Boolean"
 	case FieldTypeTimestamp:
@@ -428,11 +758,23 @@ func (f InputField) PgType() string {
 }
 
 func (f InputField) PgZeroValue() string {
+	if keyField, found := f.keyField(); found {
+		return keyField.PgZeroValue()
+	}
+
 	switch f.Type {
 	case FieldTypeString, FieldTypeAttachment:
 		return "pgtype.Text{}"
 	case FieldTypeInt:
 		return "pgtype.Int4{}"
+	case FieldTypeBigint:
+		return "pgtype.Int8{}"
+	case FieldTypeFloat:
+		return "pgtype.Float8{}"
+	case FieldTypeDecimal:
+		return "pgtype.Numeric{}"
+	case FieldTypeJSON:
+		return "nil"
 	case FieldTypeDate, FieldTypeTimestamp:
 		return "pgtype.Date{}"
 	case FieldTypeUUID, FieldTypeReferences:
@@ -447,11 +789,23 @@ func (f InputField) PgZeroValue() string {
 }
 
 func (f InputField) PgValue() string {
+	if keyField, found := f.keyField(); found {
+		return keyField.PgValue()
+	}
+
 	switch f.Type {
 	case FieldTypeString, FieldTypeAttachment:
 		return "pgtype.Text{String: *valuePtr, Valid: true}"
 	case FieldTypeInt:
 		return "pgtype.Int4{Int32: *valuePtr, Valid: true}"
+	case FieldTypeBigint:
+		return "pgtype.Int8{Int64: *valuePtr, Valid: true}"
+	case FieldTypeFloat:
+		return "pgtype.Float8{Float64: *valuePtr, Valid: true}"
+	case FieldTypeDecimal:
+		return "*valuePtr"
+	case FieldTypeJSON:
+		return "valuePtr"
 	case FieldTypeDate, FieldTypeTimestamp:
 		return "pgtype.Date{Time: *valuePtr, Valid: true}"
 	case FieldTypeUUID, FieldTypeReferences:
@@ -466,19 +820,29 @@ func (f InputField) PgValue() string {
 }
 
 func (f InputField) TypescriptType() string {
+	if f.Array {
+		return f.elementTypescriptType() + "[]"
+	}
+
+	return f.elementTypescriptType()
+}
+
+func (f InputField) elementTypescriptType() string {
 	switch f.Type {
 	case FieldTypeString:
 		return "string"
 	case FieldTypeEnum:
 		return f.Name.CamelcaseSingular()
-	case FieldTypeInt:
+	case FieldTypeJSON:
+		return f.JSONTypeName()
+	case FieldTypeInt, FieldTypeBigint, FieldTypeFloat, FieldTypeDecimal:
 		return "number"
 	case FieldTypeBool:
 		return "boolean"
 	case FieldTypeUUID:
 		return "string"
 	case FieldTypeReferences:
-		return "string"
+		return f.TableID.TypescriptType()
 	case FieldTypeAttachment:
 		return "string"
 	case FieldTypeDate:
@@ -497,7 +861,7 @@ func (f InputField) EnumTypesFrontendModel() string {
 		return ""
 	}
 
-	typeStrings := lo.Map(f.EnumValues, func(s string, _ int) string { return "'" + s + "'" })
+	typeStrings := lo.Map(f.EnumValues, func(s string, _ int) string { return sqlQuote(s) })
 
 	return "export type " + f.Name.CamelcaseSingular() + " = " + strings.Join(typeStrings, " | ")
 }
@@ -507,7 +871,7 @@ func (f InputField) FrontendModelDeclaration() string {
 }
 
 func (f InputField) FrontendInterfaceDeclaration() string {
-	str := f.Name.LowerCamelcaseSingular()
+	str := f.FrontendName()
 
 	if !f.NotNull {
 		str += "?"
@@ -518,20 +882,39 @@ func (f InputField) FrontendInterfaceDeclaration() string {
 	return str
 }
 
+// FrontendRequestDeclaration declares the field in a request type of the
+// slice. Json fields refer to their interface through the model, which is
+// where it is declared.
+func (f InputField) FrontendRequestDeclaration() string {
+	if f.Type != FieldTypeJSON {
+		return f.FrontendInterfaceDeclaration()
+	}
+
+	str := f.FrontendName()
+
+	if !f.NotNull {
+		str += "?"
+	}
+
+	str += ": NonNullable<" + f.Resource.CamelcaseSingular() + "['" + f.FrontendName() + "']>;"
+
+	return str
+}
+
 func (f InputField) FrontendModelAssignment() string {
 	str := ""
 
 	if !f.NotNull {
-		str += "\n    if (json." + f.Name.LowerCamelcaseSingular() + ") {\n      "
+		str += "\n    if (json." + f.FrontendName() + ") {\n      "
 	}
 
-	str += "this." + f.Name.LowerCamelcaseSingular() + " = "
+	str += "this." + f.FrontendName() + " = "
 
 	if f.Type == FieldTypeDate || f.Type == FieldTypeTimestamp {
 		str += "dayjs.utc("
 	}
 
-	str += "json." + f.Name.LowerCamelcaseSingular()
+	str += "json." + f.FrontendName()
 
 	if f.Type == FieldTypeDate || f.Type == FieldTypeTimestamp {
 		str += ")"
@@ -545,3 +928,7 @@ func (f InputField) FrontendModelAssignment() string {
 
 	return str
 }
+
+func sqlQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArrayFieldNames(t *testing.T) {
+	input := testInput(t, "", "Post", "tags:string[]:not_null", "author_ids:uuid[]")
+	tags := input.Fields[0]
+	authors := input.Fields[1]
+
+	tests := []struct {
+		name     string
+		fragment string
+		expected string
+	}{
+		{"params", tags.CreateParamsGoFragment(), "Tags []string"},
+		{"request", tags.CreateRequestGoFragment(), "Tags []string `json:\"tags\"`"},
+		{"presenter", tags.PresenterGoFragment(), "Tags []string `json:\"tags\"`"},
+		{"assign params", tags.CreateAssignParamsGoFragment(), "Tags: params.Tags"},
+		{"assign request", tags.CreateHandlerAssignParamsGoFragment(), "input.Tags = request.Tags"},
+		{"present", tags.PresenterAssignment(), "item.Tags = m.Tags"},
+		{"present uuids", authors.PresenterAssignment(), "item.AuthorIds = append(item.AuthorIds, id.String())"},
+		{"interface", tags.FrontendInterfaceDeclaration(), "tags: string[];"},
+		{"model", tags.FrontendModelAssignment(), "this.tags = json.tags;"},
+		{"nullable model", authors.FrontendModelAssignment(), "if (json.authorIds) {"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !strings.Contains(test.fragment, test.expected) {
+				t.Errorf("expected %q in %q", test.expected, test.fragment)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/samber/lo"
+)
+
+// fileSnapshot is the state of a file before oxgen first touched it.
+type fileSnapshot struct {
+	existed bool
+	content []byte
+}
+
+// workspace tracks every file oxgen reads and writes during a run. In dry-run
+// mode writes only land in the overlay, so later steps see earlier changes
+// without anything reaching the disk.
+type workspace struct {
+	overlay        map[string][]byte
+	deleted        map[string]bool
+	folders        map[string]bool
+	originals      map[string]fileSnapshot
+	order          []string
+	commands       []string
+	createdFolders []string
+	trackedFolders map[string]map[string]bool
+	// migrations is how many migrations the run applied, each of which a
+	// rollback steps back
+	migrations int
+	run        runRecord
+}
+
+// runRecord is what a single command produced, for the manifest. Unlike the
+// rest of the workspace it is reset even between dry runs.
+type runRecord struct {
+	created  []string
+	modified []string
+	anchors  []ManifestAnchor
+}
+
+func (r runRecord) isCreated(path string) bool {
+	for _, created := range r.created {
+		if created == path {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newWorkspace() *workspace {
+	return &workspace{
+		overlay:        map[string][]byte{},
+		deleted:        map[string]bool{},
+		folders:        map[string]bool{},
+		originals:      map[string]fileSnapshot{},
+		trackedFolders: map[string]map[string]bool{},
+	}
+}
+
+// trackFolder records the files currently inside a folder that an external
+// command is about to regenerate, so that files it adds can be removed again.
+func (w *workspace) trackFolder(path string) error {
+	if _, found := w.trackedFolders[path]; found {
+		return nil
+	}
+
+	existing := map[string]bool{}
+
+	if missing := firstMissingFolder(path); missing != "" {
+		w.createdFolders = append(w.createdFolders, missing)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read folder %s: %w", path, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		filePath := filepath.Join(path, entry.Name())
+		existing[filePath] = true
+
+		if err = w.snapshot(filePath); err != nil {
+			return err
+		}
+	}
+
+	w.trackedFolders[path] = existing
+
+	return nil
+}
+
+func (w *workspace) snapshot(path string) error {
+	if _, found := w.originals[path]; found {
+		return nil
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to snapshot %s: %w", path, err)
+	}
+
+	w.originals[path] = fileSnapshot{
+		existed: err == nil,
+		content: content,
+	}
+	w.order = append(w.order, path)
+
+	return nil
+}
+
+// trackFile snapshots a file that an external command is about to rewrite.
+func (s *Service) trackFile(path string) error {
+	if s.dryRun {
+		return nil
+	}
+
+	return s.workspace.snapshot(filepath.Clean(path))
+}
+
+// trackFolder snapshots a folder that an external command is about to rewrite.
+func (s *Service) trackFolder(path string) error {
+	if s.dryRun {
+		return nil
+	}
+
+	return s.workspace.trackFolder(filepath.Clean(path))
+}
+
+func (s *Service) readFile(path string) ([]byte, error) {
+	path = filepath.Clean(path)
+
+	if s.dryRun {
+		if content, found := s.workspace.overlay[path]; found {
+			return content, nil
+		}
+
+		if s.workspace.deleted[path] {
+			return nil, fmt.Errorf("failed to read %s: %w", path, os.ErrNotExist)
+		}
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return content, nil
+}
+
+func (s *Service) writeFile(path string, content []byte) error {
+	path = filepath.Clean(path)
+
+	if err := s.workspace.snapshot(path); err != nil {
+		return err
+	}
+
+	switch {
+	case s.workspace.run.isCreated(path):
+	case s.fileExists(path):
+		s.workspace.run.modified = appendUnique(s.workspace.run.modified, path)
+	default:
+		s.workspace.run.created = append(s.workspace.run.created, path)
+	}
+
+	if s.dryRun {
+		s.workspace.overlay[path] = content
+		delete(s.workspace.deleted, path)
+
+		return nil
+	}
+
+	//nolint:gomnd,gosec
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *Service) fileExists(path string) bool {
+	path = filepath.Clean(path)
+
+	if s.dryRun {
+		if _, found := s.workspace.overlay[path]; found {
+			return true
+		}
+
+		if s.workspace.deleted[path] {
+			return false
+		}
+	}
+
+	info, err := os.Stat(path)
+
+	return err == nil && !info.IsDir()
+}
+
+func (s *Service) removeFile(path string) error {
+	path = filepath.Clean(path)
+
+	if err := s.workspace.snapshot(path); err != nil {
+		return err
+	}
+
+	if s.dryRun {
+		delete(s.workspace.overlay, path)
+		s.workspace.deleted[path] = true
+
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// removeFolder removes a folder one file at a time, so that each file can be
+// diffed in a dry run and restored on rollback.
+func (s *Service) removeFolder(path string) error {
+	path = filepath.Clean(path)
+
+	if !s.folderExists(path) {
+		return nil
+	}
+
+	if err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		return s.removeFile(filePath)
+	}); err != nil {
+		return fmt.Errorf("failed to remove folder %s: %w", path, err)
+	}
+
+	if s.dryRun {
+		delete(s.workspace.folders, path)
+
+		return nil
+	}
+
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove folder %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *Service) folderExists(path string) bool {
+	path = filepath.Clean(path)
+
+	if s.dryRun && s.workspace.folders[path] {
+		return true
+	}
+
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+func (s *Service) makeFolder(path string) error {
+	path = filepath.Clean(path)
+
+	if s.dryRun {
+		s.workspace.folders[path] = true
+
+		return nil
+	}
+
+	if missing := firstMissingFolder(path); missing != "" {
+		s.workspace.createdFolders = append(s.workspace.createdFolders, missing)
+	}
+
+	//nolint:gomnd
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return fmt.Errorf("failed to create folder %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func firstMissingFolder(path string) string {
+	missing := ""
+
+	for current := path; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(current); err == nil {
+			return missing
+		}
+
+		missing = current
+
+		if filepath.Dir(current) == current {
+			return missing
+		}
+	}
+}
+
+// globFiles lists the files in a folder whose names match the pattern,
+// including files that only exist in the dry-run overlay.
+func (s *Service) globFiles(folder string, pattern string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(folder, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", folder, err)
+	}
+
+	paths = lo.Filter(paths, func(path string, _ int) bool { return s.fileExists(path) })
+
+	for path := range s.workspace.overlay {
+		if matched, _ := filepath.Match(pattern, filepath.Base(path)); matched && filepath.Dir(path) == filepath.Clean(folder) {
+			paths = appendUnique(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	return paths, nil
+}
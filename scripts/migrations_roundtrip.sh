@@ -0,0 +1,60 @@
+#!/bin/sh
+# migrations_roundtrip.sh checks that every down migration of a project is the
+# exact inverse of its up migration. Each migration is applied, rolled back and
+# applied again, and the schema is compared with pg_dump after every step.
+#
+# Usage: scripts/migrations_roundtrip.sh <project folder> <database>
+#
+# The database is wiped first, so point it at a scratch database.
+set -eu
+
+project=${1:-webapp}
+database=${2:-webapp}
+url="postgres://127.0.0.1/${database}?sslmode=disable"
+work=$(mktemp -d)
+trap 'rm -rf "$work"' EXIT
+
+dump() {
+  pg_dump --schema-only -O "$database" > "$work/$1.sql"
+}
+
+migrate_project() {
+  migrate -path "$project/migrations" -database "$url" "$@"
+}
+
+# extensions are shared by every table, so down migrations leave them
+# installed, and they are installed up front to keep them out of the diffs
+psql -q "$database" -c "DROP SCHEMA public CASCADE; CREATE SCHEMA public;"
+psql -q "$database" -c "CREATE EXTENSION IF NOT EXISTS moddatetime; CREATE EXTENSION IF NOT EXISTS pg_trgm;"
+
+# migrate creates its version table on first use, even with nothing to apply
+migrate_project version 2>/dev/null || true
+
+count=$(find "$project/migrations" -name '*.up.sql' | wc -l)
+step=0
+
+while [ "$step" -lt "$count" ]; do
+  step=$((step + 1))
+  name=$(find "$project/migrations" -name '*.up.sql' | sort | sed -n "${step}p")
+
+  dump before
+  migrate_project up 1
+  dump up
+  migrate_project down 1
+  dump down
+
+  if ! diff -u "$work/before.sql" "$work/down.sql"; then
+    echo "FAIL: down migration of $name does not reverse it" >&2
+    exit 1
+  fi
+
+  migrate_project up 1
+  dump again
+
+  if ! diff -u "$work/up.sql" "$work/again.sql"; then
+    echo "FAIL: $name does not apply the same way after a rollback" >&2
+    exit 1
+  fi
+
+  echo "ok: $name"
+done